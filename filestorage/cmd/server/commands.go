@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"filestorage/internal/application/dto"
+	"filestorage/internal/application/usecase"
+	"filestorage/internal/domain/repository"
+	"filestorage/internal/infrastructure/config"
+	"filestorage/migrations"
+)
+
+func runCommand(name string, args []string) {
+	switch name {
+	case "purge":
+		runPurge(args)
+	case "reconcile":
+		runReconcile(args)
+	case "rotate-keys":
+		runRotateKeys(args)
+	case "import":
+		runImport(args)
+	case "migrate":
+		runMigrate(args)
+	default:
+		log.Fatalf("unknown command %q (available: purge, reconcile, rotate-keys, import, migrate)", name)
+	}
+}
+
+// runPurge erases all data of one author: filestorage purge -author <id>.
+func runPurge(args []string) {
+	flags := flag.NewFlagSet("purge", flag.ExitOnError)
+	authorID := flags.String("author", "", "author_id whose data should be erased")
+	requestedBy := flags.String("requested-by", "cli", "who asked for the erasure, stored in the audit record")
+	_ = flags.Parse(args)
+
+	if *authorID == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	deps, err := newDependencies(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer deps.close()
+
+	uc := usecase.NewPurgeAuthorUseCase(deps.submissionRepo, deps.s3Repo, deps.notifier)
+	resp, err := uc.Purge(ctx, dto.PurgeAuthorRequest{
+		AuthorID:    *authorID,
+		RequestedBy: *requestedBy,
+	})
+	if err != nil {
+		deps.close()
+		log.Fatalf("purge failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(resp)
+}
+
+// runReconcile compares rows with stored objects once and prints the report:
+// filestorage reconcile [-repair].
+func runReconcile(args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "delete orphan objects and mark rows without files as file_missing")
+	grace := flags.Duration("grace", config.LoadReconcileConfig().Grace, "ignore rows and objects younger than this")
+	_ = flags.Parse(args)
+
+	ctx := context.Background()
+	deps, err := newDependencies(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer deps.close()
+
+	uc := usecase.NewReconcileUseCase(deps.submissionRepo, deps.s3Repo, *grace)
+	report, err := uc.Reconcile(ctx, *repair)
+	if err != nil {
+		deps.close()
+		log.Fatalf("reconcile failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+}
+
+// runRotateKeys re-wraps stored data keys with ENCRYPTION_ACTIVE_KEY. Run it
+// after switching the active key and before removing the old one from
+// ENCRYPTION_KEYS.
+func runRotateKeys(args []string) {
+	flags := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	ctx := context.Background()
+	deps, err := newDependencies(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer deps.close()
+
+	rotator, ok := deps.s3Repo.(repository.KeyRotator)
+	if !ok {
+		deps.close()
+		log.Fatal("rotate-keys: encryption is not enabled (ENCRYPTION_KEYS is empty)")
+	}
+
+	rewrapped, current, err := rotator.RotateKeys(ctx)
+	if err != nil {
+		deps.close()
+		log.Fatalf("rotate-keys failed after re-wrapping %d data keys: %v", rewrapped, err)
+	}
+	log.Printf("rotate-keys: re-wrapped %d data keys, %d already used the active key", rewrapped, current)
+}
+
+// runImport loads an LMS archive into an assignment and prints the report:
+// filestorage import -assignment <id> [-format moodle|csv] [-authors map.csv] archive.zip.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	assignmentID := flags.String("assignment", "", "assignment_id to import into")
+	format := flags.String("format", usecase.ImportFormatMoodle, "archive layout: moodle or csv")
+	authorsPath := flags.String("authors", "", "participant_id,author_id CSV for Moodle archives")
+	_ = flags.Parse(args)
+
+	if *assignmentID == "" || flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	archive, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer archive.Close()
+	info, err := archive.Stat()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	req := dto.ImportRequest{
+		AssignmentID: *assignmentID,
+		Format:       *format,
+		Archive:      archive,
+		ArchiveSize:  info.Size(),
+	}
+	if *authorsPath != "" {
+		f, err := os.Open(*authorsPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		req.Authors, err = usecase.ParseImportAuthors(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	uploadPolicy, err := config.LoadUploadPolicy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	malwareScanner, err := newScanner(config.LoadScannerConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	deps, err := newDependencies(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer deps.close()
+
+	// The server's outbox dispatcher starts the checks once they are released.
+	submit := usecase.NewSubmitUseCase(deps.submissionRepo, deps.s3Repo, deps.outboxRepo, nil, malwareScanner, usecase.Quota{}, newUploadPolicy(uploadPolicy))
+	uc := usecase.NewImportUseCase(submit, deps.submissionRepo, deps.outboxRepo, config.MaxUploadSize())
+	resp, err := uc.Import(ctx, req)
+	if err != nil {
+		deps.close()
+		log.Fatalf("import failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(resp)
+}
+
+// runMigrate manages the database schema regardless of DATABASE_AUTO_MIGRATE:
+// filestorage migrate up | down [-steps N] | status.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage: migrate up | down [-steps N] | status")
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	steps := 0
+	if action == "down" {
+		flags.IntVar(&steps, "steps", 1, "how many of the latest migrations to revert")
+	}
+	_ = flags.Parse(args[1:])
+
+	ctx := context.Background()
+	m, closeDB, err := newMigrator(ctx, config.LoadDatabaseConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeDB()
+
+	var result any
+	switch action {
+	case "up":
+		var applied []migrations.Migration
+		applied, err = m.Up(ctx)
+		result = map[string][]string{"applied": migrationNames(applied)}
+	case "down":
+		if steps < 1 {
+			closeDB()
+			log.Fatal("-steps must be positive")
+		}
+		var reverted []migrations.Migration
+		reverted, err = m.Down(ctx, steps)
+		result = map[string][]string{"reverted": migrationNames(reverted)}
+	case "status":
+		result, err = m.Status(ctx)
+	default:
+		closeDB()
+		log.Fatalf("unknown migrate action %q (available: up, down, status)", action)
+	}
+	if err != nil {
+		closeDB()
+		log.Fatalf("migrate %s failed: %v", action, err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(result)
+}
+
+func migrationNames(list []migrations.Migration) []string {
+	names := make([]string, 0, len(list))
+	for _, m := range list {
+		names = append(names, m.Name)
+	}
+	return names
+}
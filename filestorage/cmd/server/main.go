@@ -12,40 +12,101 @@ import (
 
 	"filestorage/internal/api/http/router"
 	"filestorage/internal/application/usecase"
+	"filestorage/internal/domain/repository"
 	"filestorage/internal/infrastructure/config"
+	"filestorage/internal/infrastructure/plagiarism"
+	"filestorage/internal/infrastructure/repository/encrypted"
+	"filestorage/internal/infrastructure/repository/fs"
+	"filestorage/internal/infrastructure/repository/memory"
 	"filestorage/internal/infrastructure/repository/postgres"
 	"filestorage/internal/infrastructure/repository/s3"
+	"filestorage/internal/infrastructure/repository/sqlite"
+	"filestorage/internal/infrastructure/scanner"
+	"filestorage/migrations"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
-	ctx := context.Background()
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
 
-	dbConfig := config.LoadDatabaseConfig()
-	s3Config := config.LoadS3Config()
+	ctx := context.Background()
 
-	pool, err := pgxpool.New(ctx, dbConfig.DSN)
+	uploadPolicy, err := config.LoadUploadPolicy()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatal(err)
 	}
-	defer pool.Close()
+	policy := newUploadPolicy(uploadPolicy)
 
-	if err := pool.Ping(ctx); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+	malwareScanner, err := newScanner(config.LoadScannerConfig())
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	submissionRepo := postgres.NewPostgresRepository(pool)
-	s3Repo, err := s3.NewS3Repository(ctx, s3Config.Bucket, s3Config.Endpoint, s3Config.Region)
+	deps, err := newDependencies(ctx)
 	if err != nil {
-		log.Fatalf("Failed to initialize S3 repository: %v", err)
+		log.Fatal(err)
+	}
+	defer deps.close()
+
+	submissionRepo, s3Repo := deps.submissionRepo, deps.s3Repo
+
+	quotaConfig := config.LoadQuotaConfig()
+	quota := usecase.Quota{
+		MaxSubmissionsPerAssignment: quotaConfig.MaxSubmissionsPerAssignment,
+		MaxBytesPerAuthor:           quotaConfig.MaxBytesPerAuthor,
 	}
 
-	submitUseCase := usecase.NewSubmitUseCase(submissionRepo, s3Repo)
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+
+	// Without PLAGIARISM_URL events stay in the outbox until a restart with
+	// it set delivers them.
+	var events *usecase.OutboxDispatcher
+	if deps.checks != nil {
+		outboxConfig := config.LoadOutboxConfig()
+		events = usecase.NewOutboxDispatcher(deps.outboxRepo, deps.checks, deps.deletions, outboxConfig.PollInterval, outboxConfig.MaxBackoff)
+		go events.Run(jobsCtx)
+	}
+
+	submitUseCase := usecase.NewSubmitUseCase(submissionRepo, s3Repo, deps.outboxRepo, events, malwareScanner, quota, policy)
 	getSubmissionsUseCase := usecase.NewGetSubmissionsUseCase(submissionRepo)
-	downloadSubmissionUseCase := usecase.NewDownloadSubmissionUseCase(submissionRepo, s3Repo)
+	// Encrypted stores do not presign: the URL would serve ciphertext.
+	presigner, _ := s3Repo.(repository.Presigner)
+	downloadSubmissionUseCase := usecase.NewDownloadSubmissionUseCase(submissionRepo, s3Repo, presigner, config.LoadS3Config().PresignTTL)
+	deleteSubmissionUseCase := usecase.NewDeleteSubmissionUseCase(submissionRepo, s3Repo, deps.outboxRepo, events)
+	purgeAuthorUseCase := usecase.NewPurgeAuthorUseCase(submissionRepo, s3Repo, deps.notifier)
+
+	reconcileConfig := config.LoadReconcileConfig()
+	reconcileUseCase := usecase.NewReconcileUseCase(submissionRepo, s3Repo, reconcileConfig.Grace)
+	quotaUseCase := usecase.NewQuotaUseCase(submissionRepo, quota)
+	exportUseCase := usecase.NewExportAssignmentUseCase(submissionRepo, s3Repo, deps.reports)
+	importUseCase := usecase.NewImportUseCase(submitUseCase, submissionRepo, deps.outboxRepo, config.MaxUploadSize())
 
-	r := router.NewRouter(submitUseCase, getSubmissionsUseCase, downloadSubmissionUseCase)
+	if reconcileConfig.Interval > 0 {
+		go reconcileUseCase.RunPeriodically(jobsCtx, reconcileConfig.Interval, reconcileConfig.Repair)
+	}
+
+	idempotencyConfig := config.LoadIdempotencyConfig()
+	idempotencyUseCase := usecase.NewIdempotencyUseCase(deps.idempotencyRepo, idempotencyConfig.TTL)
+	go idempotencyUseCase.RunPeriodically(jobsCtx, time.Hour)
+
+	r := router.NewRouter(
+		submitUseCase,
+		getSubmissionsUseCase,
+		downloadSubmissionUseCase,
+		deleteSubmissionUseCase,
+		purgeAuthorUseCase,
+		reconcileUseCase,
+		quotaUseCase,
+		exportUseCase,
+		importUseCase,
+		idempotencyUseCase,
+		config.AdminToken(),
+	)
 	handler := r.SetupRoutes()
 
 	port := ":" + config.ServerPort()
@@ -75,3 +136,186 @@ func main() {
 
 	fmt.Println("Server exited")
 }
+
+type dependencies struct {
+	submissionRepo  repository.SubmissionRepository
+	outboxRepo      repository.OutboxRepository
+	idempotencyRepo repository.IdempotencyRepository
+	s3Repo          repository.S3Repository
+	notifier        usecase.DeletionNotifier
+	deletions       usecase.SubmissionDeletionNotifier
+	reports         usecase.ReportsFetcher
+	checks          usecase.CheckStarter
+	close           func()
+}
+
+// newDependencies builds the repositories and clients shared by the server
+// and the maintenance subcommands.
+func newDependencies(ctx context.Context) (*dependencies, error) {
+	dbConfig := config.LoadDatabaseConfig()
+	s3Config := config.LoadS3Config()
+	storageConfig := config.LoadStorageConfig()
+
+	submissionRepo, outboxRepo, idempotencyRepo, closeDB, err := newRepositories(ctx, dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s database: %w", dbConfig.Backend, err)
+	}
+
+	s3Repo, err := newObjectRepository(ctx, storageConfig, s3Config)
+	if err != nil {
+		closeDB()
+		return nil, fmt.Errorf("failed to initialize %s object storage: %w", storageConfig.Backend, err)
+	}
+
+	encryptionConfig, err := config.LoadEncryptionConfig()
+	if err != nil {
+		closeDB()
+		return nil, err
+	}
+	if encryptionConfig.Enabled() {
+		keyring, err := encrypted.NewKeyring(encryptionConfig.Keys, encryptionConfig.ActiveKeyID)
+		if err != nil {
+			closeDB()
+			return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+		}
+		s3Repo = encrypted.NewEncryptedRepository(s3Repo, keyring)
+	}
+
+	var (
+		notifier  usecase.DeletionNotifier
+		deletions usecase.SubmissionDeletionNotifier
+		reports   usecase.ReportsFetcher
+		checks    usecase.CheckStarter
+	)
+	if plagiarismURL := config.PlagiarismURL(); plagiarismURL != "" {
+		client := plagiarism.NewClient(plagiarismURL)
+		notifier, deletions, reports, checks = client, client, client, client
+	}
+
+	return &dependencies{
+		submissionRepo:  submissionRepo,
+		outboxRepo:      outboxRepo,
+		idempotencyRepo: idempotencyRepo,
+		s3Repo:          s3Repo,
+		notifier:        notifier,
+		deletions:       deletions,
+		reports:         reports,
+		checks:          checks,
+		close:           closeDB,
+	}, nil
+}
+
+func newRepositories(ctx context.Context, dbConfig *config.DatabaseConfig) (repository.SubmissionRepository, repository.OutboxRepository, repository.IdempotencyRepository, func(), error) {
+	switch dbConfig.Backend {
+	case config.DatabaseBackendPostgres:
+		pool, err := openPostgres(ctx, dbConfig.DSN)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if dbConfig.AutoMigrate {
+			if err := migrateUp(ctx, postgres.NewMigrator(pool)); err != nil {
+				pool.Close()
+				return nil, nil, nil, nil, err
+			}
+		}
+		return postgres.NewPostgresRepository(pool), postgres.NewPostgresOutboxRepository(pool), postgres.NewPostgresIdempotencyRepository(pool), pool.Close, nil
+	case config.DatabaseBackendSQLite:
+		db, err := sqlite.Open(ctx, dbConfig.SQLitePath)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if dbConfig.AutoMigrate {
+			if err := migrateUp(ctx, sqlite.NewMigrator(db)); err != nil {
+				_ = db.Close()
+				return nil, nil, nil, nil, err
+			}
+		}
+		return sqlite.NewSQLiteRepository(db), sqlite.NewSQLiteOutboxRepository(db), sqlite.NewSQLiteIdempotencyRepository(db), func() { _ = db.Close() }, nil
+	default:
+		return nil, nil, nil, nil, fmt.Errorf("unknown DATABASE_BACKEND %q", dbConfig.Backend)
+	}
+}
+
+type migrator interface {
+	Up(ctx context.Context) ([]migrations.Migration, error)
+	Down(ctx context.Context, steps int) ([]migrations.Migration, error)
+	Status(ctx context.Context) ([]migrations.Status, error)
+}
+
+// newMigrator opens the configured database without touching its schema.
+func newMigrator(ctx context.Context, dbConfig *config.DatabaseConfig) (migrator, func(), error) {
+	switch dbConfig.Backend {
+	case config.DatabaseBackendPostgres:
+		pool, err := openPostgres(ctx, dbConfig.DSN)
+		if err != nil {
+			return nil, nil, err
+		}
+		return postgres.NewMigrator(pool), pool.Close, nil
+	case config.DatabaseBackendSQLite:
+		db, err := sqlite.Open(ctx, dbConfig.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlite.NewMigrator(db), func() { _ = db.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown DATABASE_BACKEND %q", dbConfig.Backend)
+	}
+}
+
+func migrateUp(ctx context.Context, m migrator) error {
+	applied, err := m.Up(ctx)
+	if err != nil {
+		return err
+	}
+	for _, mig := range applied {
+		log.Printf("applied migration %s", mig.Name)
+	}
+	return nil
+}
+
+func openPostgres(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+func newObjectRepository(ctx context.Context, storageConfig *config.StorageConfig, s3Config *config.S3Config) (repository.S3Repository, error) {
+	switch storageConfig.Backend {
+	case config.StorageBackendS3:
+		return s3.NewS3Repository(ctx, s3Config.Bucket, s3Config.Endpoint, s3Config.PublicEndpoint, s3Config.Region)
+	case config.StorageBackendFS:
+		return fs.NewFSRepository(storageConfig.FSRoot)
+	case config.StorageBackendMemory:
+		return memory.NewMemoryRepository(), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", storageConfig.Backend)
+	}
+}
+
+func newScanner(scannerConfig *config.ScannerConfig) (usecase.MalwareScanner, error) {
+	switch scannerConfig.Backend {
+	case config.ScannerBackendNone:
+		return scanner.NewNoopScanner(), nil
+	case config.ScannerBackendClamd:
+		return scanner.NewClamdScanner(scannerConfig.ClamdAddress, scannerConfig.ClamdTimeout)
+	default:
+		return nil, fmt.Errorf("unknown SCANNER_BACKEND %q", scannerConfig.Backend)
+	}
+}
+
+func newUploadPolicy(policyConfig *config.UploadPolicy) usecase.UploadPolicy {
+	policy := usecase.UploadPolicy{
+		Default:     usecase.AllowList(policyConfig.Default),
+		Assignments: make(map[string]usecase.AllowList, len(policyConfig.Assignments)),
+	}
+	for id, list := range policyConfig.Assignments {
+		policy.Assignments[id] = usecase.AllowList(list)
+	}
+	return policy
+}
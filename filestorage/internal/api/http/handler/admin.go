@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	apperr "filestorage/internal/common/errors"
+)
+
+// requireAdmin wraps admin handlers with a static bearer token check. With no
+// token configured the admin routes stay closed.
+func requireAdmin(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			respondError(w, apperr.New(apperr.CodeForbidden, "admin token required"))
+			return
+		}
+		next(w, r)
+	}
+}
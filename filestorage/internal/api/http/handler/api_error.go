@@ -38,10 +38,22 @@ func mapAPIError(err error) apiError {
 		return apiError{status: http.StatusBadRequest, code: code, message: message}
 	case apperr.CodeNotFound:
 		return apiError{status: http.StatusNotFound, code: code, message: message}
+	case apperr.CodeForbidden:
+		return apiError{status: http.StatusForbidden, code: code, message: message}
 	case apperr.CodeStorage:
 		return apiError{status: http.StatusBadGateway, code: code, message: message}
 	case apperr.CodeDatabase:
 		return apiError{status: http.StatusInternalServerError, code: code, message: message}
+	case apperr.CodeUnsupported:
+		return apiError{status: http.StatusNotImplemented, code: code, message: message}
+	case apperr.CodeUnavailable:
+		return apiError{status: http.StatusServiceUnavailable, code: code, message: message}
+	case apperr.CodeQuotaExceeded:
+		return apiError{status: http.StatusTooManyRequests, code: code, message: message}
+	case apperr.CodeConflict:
+		return apiError{status: http.StatusConflict, code: code, message: message}
+	case apperr.CodeStorageQuotaExceeded:
+		return apiError{status: http.StatusRequestEntityTooLarge, code: code, message: message}
 	default:
 		return apiError{status: http.StatusInternalServerError, code: apperr.CodeInternal, message: defaultMessageForCode(apperr.CodeInternal)}
 	}
@@ -53,10 +65,22 @@ func defaultMessageForCode(code apperr.Code) string {
 		return "validation error"
 	case apperr.CodeNotFound:
 		return "resource not found"
+	case apperr.CodeForbidden:
+		return "forbidden"
 	case apperr.CodeStorage:
 		return "storage error"
 	case apperr.CodeDatabase:
 		return "database error"
+	case apperr.CodeUnsupported:
+		return "not supported"
+	case apperr.CodeUnavailable:
+		return "service unavailable"
+	case apperr.CodeQuotaExceeded:
+		return "submission quota exceeded"
+	case apperr.CodeStorageQuotaExceeded:
+		return "storage quota exceeded"
+	case apperr.CodeConflict:
+		return "conflict"
 	default:
 		return "internal error"
 	}
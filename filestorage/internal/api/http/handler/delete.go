@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"filestorage/internal/application/usecase"
+)
+
+type DeleteHandler struct {
+	useCase *usecase.DeleteSubmissionUseCase
+}
+
+func NewDeleteHandler(useCase *usecase.DeleteSubmissionUseCase) *DeleteHandler {
+	return &DeleteHandler{useCase: useCase}
+}
+
+func (h *DeleteHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondMethodNotAllowed(w, "only DELETE method is allowed")
+		return
+	}
+
+	submissionID := strings.TrimPrefix(r.URL.Path, "/submissions/")
+	if submissionID == "" || strings.Contains(submissionID, "/") {
+		respondValidationError(w, "expected /submissions/{submission_id}")
+		return
+	}
+
+	if err := h.useCase.Delete(r.Context(), submissionID, r.URL.Query().Get("assignment_id")); err != nil {
+		log.Printf("delete: submission_id=%s failed: %v", submissionID, err)
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 
 	"filestorage/internal/application/usecase"
 )
@@ -29,19 +31,91 @@ func (h *DownloadHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.useCase.Download(r.Context(), submissionID)
+	resp, err := h.useCase.Stat(r.Context(), submissionID)
 	if err != nil {
 		log.Printf("download: submission_id=%s failed: %v", submissionID, err)
 		respondError(w, err)
 		return
 	}
+
+	// Stored files never change, so the checksum is a strong validator and
+	// the upload time is the last modification.
+	var etag string
+	if resp.ETag != "" {
+		etag = `"` + resp.ETag + `"`
+		w.Header().Set("ETag", etag)
+	}
+	if !resp.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", resp.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(r, etag, resp.LastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// Ranges need the size up front; legacy rows without it get the whole file.
+	sizeKnown := etag != ""
+	offset, length, status := int64(0), int64(-1), http.StatusOK
+	if sizeKnown {
+		w.Header().Set("Accept-Ranges", "bytes")
+		if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && rangeApplies(r, etag, resp.LastModified) {
+			start, n, ok, err := parseRange(rangeHeader, resp.Size)
+			if err != nil {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", resp.Size))
+				writeJSONError(w, http.StatusRequestedRangeNotSatisfiable, "range_not_satisfiable", "requested range is outside the file")
+				return
+			}
+			if ok {
+				offset, length, status = start, n, http.StatusPartialContent
+			}
+		}
+	}
+
+	if err := h.useCase.Open(r.Context(), resp, offset, length); err != nil {
+		log.Printf("download: submission_id=%s failed: %v", submissionID, err)
+		respondError(w, err)
+		return
+	}
 	defer resp.File.Close()
 
 	w.Header().Set("Content-Type", resp.ContentType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, resp.Filename))
-	w.WriteHeader(http.StatusOK)
+	switch {
+	case status == http.StatusPartialContent:
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, resp.Size))
+		w.Header().Set("Content-Length", strconv.FormatInt(length, 10))
+	case sizeKnown:
+		w.Header().Set("Content-Length", strconv.FormatInt(resp.Size, 10))
+	}
+	w.WriteHeader(status)
 
 	if _, err := io.Copy(w, resp.File); err != nil {
 		log.Printf("download: submission_id=%s stream failed: %v", submissionID, err)
 	}
 }
+
+// HandleURL answers with a presigned URL instead of the file itself.
+func (h *DownloadHandler) HandleURL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET method is allowed")
+		return
+	}
+
+	submissionID := r.URL.Query().Get("submission_id")
+	if submissionID == "" {
+		respondValidationError(w, "submission_id query parameter is required")
+		return
+	}
+
+	resp, err := h.useCase.DownloadURL(r.Context(), submissionID)
+	if err != nil {
+		log.Printf("download-url: submission_id=%s failed: %v", submissionID, err)
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"filestorage/internal/application/dto"
+	"filestorage/internal/application/usecase"
+)
+
+type ExportHandler struct {
+	useCase *usecase.ExportAssignmentUseCase
+}
+
+func NewExportHandler(useCase *usecase.ExportAssignmentUseCase) *ExportHandler {
+	return &ExportHandler{useCase: useCase}
+}
+
+func (h *ExportHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET method is allowed")
+		return
+	}
+
+	assignmentID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/assignments/"), "/export")
+	if !ok || assignmentID == "" {
+		respondValidationError(w, "expected /assignments/{assignment_id}/export")
+		return
+	}
+
+	req := dto.ExportRequest{AssignmentID: assignmentID}
+	if v := r.URL.Query().Get("include_reports"); v != "" {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			respondValidationError(w, "include_reports must be a boolean")
+			return
+		}
+		req.IncludeReports = include
+	}
+
+	export, err := h.useCase.Prepare(r.Context(), req)
+	if err != nil {
+		log.Printf("export: assignment_id=%s failed: %v", assignmentID, err)
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": assignmentID + ".zip"}))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	// The status is already sent, so a failure can only cut the archive
+	// short; the client sees a truncated zip.
+	if err := h.useCase.Write(r.Context(), export, w); err != nil {
+		log.Printf("export: assignment_id=%s aborted: %v", assignmentID, err)
+		panic(http.ErrAbortHandler)
+	}
+	log.Printf("export: assignment_id=%s done", assignmentID)
+}
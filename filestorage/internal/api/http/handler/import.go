@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"filestorage/internal/application/dto"
+	"filestorage/internal/application/usecase"
+	"filestorage/internal/infrastructure/config"
+)
+
+type ImportHandler struct {
+	useCase *usecase.ImportUseCase
+	handle  http.HandlerFunc
+}
+
+func NewImportHandler(useCase *usecase.ImportUseCase, adminToken string) *ImportHandler {
+	h := &ImportHandler{useCase: useCase}
+	h.handle = requireAdmin(adminToken, h.importArchive)
+	return h
+}
+
+func (h *ImportHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, "only POST method is allowed")
+		return
+	}
+	h.handle(w, r)
+}
+
+// importArchive takes a multipart form with assignment_id, format, the
+// archive and, for Moodle, an optional authors mapping. The archive is
+// spooled to disk because zip needs random access, so the request size is
+// capped.
+func (h *ImportHandler) importArchive(w http.ResponseWriter, r *http.Request) {
+	maxImportSize := config.MaxImportSize()
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		respondValidationError(w, "invalid multipart form")
+		return
+	}
+
+	req := dto.ImportRequest{Format: usecase.ImportFormatMoodle}
+	var archive *os.File
+	defer func() {
+		if archive != nil {
+			archive.Close()
+			os.Remove(archive.Name())
+		}
+	}()
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("import: failed to read multipart part: %v", err)
+			respondValidationError(w, "invalid multipart form")
+			return
+		}
+
+		switch part.FormName() {
+		case "assignment_id", "format":
+			body, readErr := io.ReadAll(io.LimitReader(part, 1024))
+			if readErr != nil {
+				_ = part.Close()
+				respondValidationError(w, "failed to read "+part.FormName())
+				return
+			}
+			if part.FormName() == "format" {
+				req.Format = string(body)
+			} else {
+				req.AssignmentID = string(body)
+			}
+		case "authors":
+			req.Authors, err = usecase.ParseImportAuthors(part)
+			if err != nil {
+				_ = part.Close()
+				respondError(w, err)
+				return
+			}
+		case "archive":
+			if archive != nil {
+				_ = part.Close()
+				respondValidationError(w, "only one archive is allowed")
+				return
+			}
+			archive, err = os.CreateTemp("", "filestorage-import-*.zip")
+			if err != nil {
+				_ = part.Close()
+				log.Printf("import: failed to create temp file: %v", err)
+				respondError(w, err)
+				return
+			}
+			req.ArchiveSize, err = io.Copy(archive, part)
+			if err != nil {
+				_ = part.Close()
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					respondValidationError(w, fmt.Sprintf("request exceeds max import size %d bytes", maxImportSize))
+					return
+				}
+				log.Printf("import: failed to spool archive: %v", err)
+				respondValidationError(w, "failed to read archive")
+				return
+			}
+		}
+		_ = part.Close()
+	}
+
+	if archive == nil {
+		respondValidationError(w, "archive is required")
+		return
+	}
+	req.Archive = archive
+
+	resp, err := h.useCase.Import(r.Context(), req)
+	if err != nil {
+		log.Printf("import: assignment_id=%s failed: %v", req.AssignmentID, err)
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
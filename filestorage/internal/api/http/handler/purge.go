@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"filestorage/internal/application/dto"
+	"filestorage/internal/application/usecase"
+)
+
+type PurgeHandler struct {
+	useCase *usecase.PurgeAuthorUseCase
+	handle  http.HandlerFunc
+}
+
+func NewPurgeHandler(useCase *usecase.PurgeAuthorUseCase, adminToken string) *PurgeHandler {
+	h := &PurgeHandler{useCase: useCase}
+	h.handle = requireAdmin(adminToken, h.purge)
+	return h
+}
+
+func (h *PurgeHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, "only POST method is allowed")
+		return
+	}
+	h.handle(w, r)
+}
+
+func (h *PurgeHandler) purge(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		AuthorID    string `json:"author_id"`
+		RequestedBy string `json:"requested_by"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		respondValidationError(w, "failed to parse request body")
+		return
+	}
+	if request.AuthorID == "" {
+		respondValidationError(w, "author_id is required")
+		return
+	}
+
+	resp, err := h.useCase.Purge(r.Context(), dto.PurgeAuthorRequest{
+		AuthorID:    request.AuthorID,
+		RequestedBy: request.RequestedBy,
+	})
+	if err != nil {
+		log.Printf("purge: failed: %v", err)
+		respondError(w, err)
+		return
+	}
+
+	log.Printf("purge: audit_id=%s submissions=%d", resp.AuditID, len(resp.SubmissionIDs))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"filestorage/internal/application/usecase"
+)
+
+type QuotaHandler struct {
+	useCase *usecase.QuotaUseCase
+}
+
+func NewQuotaHandler(useCase *usecase.QuotaUseCase) *QuotaHandler {
+	return &QuotaHandler{useCase: useCase}
+}
+
+func (h *QuotaHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET method is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	resp, err := h.useCase.Usage(r.Context(), query.Get("author_id"), query.Get("assignment_id"))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
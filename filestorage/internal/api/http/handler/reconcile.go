@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"filestorage/internal/application/usecase"
+)
+
+type ReconcileHandler struct {
+	useCase *usecase.ReconcileUseCase
+	handle  http.HandlerFunc
+}
+
+func NewReconcileHandler(useCase *usecase.ReconcileUseCase, adminToken string) *ReconcileHandler {
+	h := &ReconcileHandler{useCase: useCase}
+	h.handle = requireAdmin(adminToken, h.reconcile)
+	return h
+}
+
+func (h *ReconcileHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, "only POST method is allowed")
+		return
+	}
+	h.handle(w, r)
+}
+
+func (h *ReconcileHandler) reconcile(w http.ResponseWriter, r *http.Request) {
+	repair := false
+	if v := r.URL.Query().Get("repair"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondValidationError(w, "repair must be a boolean")
+			return
+		}
+		repair = parsed
+	}
+
+	report, err := h.useCase.Reconcile(r.Context(), repair)
+	if err != nil {
+		log.Printf("reconcile: failed: %v", err)
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
@@ -4,8 +4,14 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"filestorage/internal/application/dto"
 	"filestorage/internal/application/usecase"
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
 )
 
 type SubmissionsHandler struct {
@@ -24,34 +30,108 @@ func (h *SubmissionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	assignmentID := r.URL.Query().Get("assignment_id")
+	query := r.URL.Query()
+	assignmentID := query.Get("assignment_id")
 	if assignmentID == "" {
 		respondValidationError(w, "assignment_id query parameter is required")
 		return
 	}
 
-	submissions, err := h.getSubmissionsUseCase.GetByAssignmentID(r.Context(), assignmentID)
+	req := dto.ListSubmissionsRequest{
+		AssignmentID: assignmentID,
+		Cursor:       query.Get("cursor"),
+		Sort:         query.Get("sort"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			respondValidationError(w, "limit must be an integer")
+			return
+		}
+		req.Limit = limit
+	}
+
+	var ok bool
+	if req.CreatedAfter, ok = parseTimeParam(w, query.Get("created_after"), "created_after"); !ok {
+		return
+	}
+	if req.CreatedBefore, ok = parseTimeParam(w, query.Get("created_before"), "created_before"); !ok {
+		return
+	}
+
+	resp, err := h.getSubmissionsUseCase.List(r.Context(), req)
 	if err != nil {
 		log.Printf("submissions: assignment_id=%s failed: %v", assignmentID, err)
 		respondError(w, err)
 		return
 	}
 
-	submissionsResponse := make([]map[string]interface{}, 0, len(submissions))
-	for _, sub := range submissions {
-		submissionsResponse = append(submissionsResponse, map[string]interface{}{
-			"submission_id": sub.SubmissionID.String(),
-			"assignment_id": sub.AssignmentID,
-			"author_id":     sub.AuthorID,
-			"created_at":    sub.CreatedAt,
-		})
+	submissionsResponse := make([]map[string]interface{}, 0, len(resp.Submissions))
+	for _, sub := range resp.Submissions {
+		submissionsResponse = append(submissionsResponse, submissionResponse(sub))
 	}
 
 	response := map[string]interface{}{
 		"submissions": submissionsResponse,
 	}
+	if resp.NextCursor != "" {
+		response["next_cursor"] = resp.NextCursor
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// HandleOne serves GET /submissions/{submission_id} with the same fields as
+// a list item.
+func (h *SubmissionsHandler) HandleOne(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET method is allowed")
+		return
+	}
+
+	submissionID := strings.TrimPrefix(r.URL.Path, "/submissions/")
+	if submissionID == "" || strings.Contains(submissionID, "/") {
+		respondValidationError(w, "expected /submissions/{submission_id}")
+		return
+	}
+
+	sub, err := h.getSubmissionsUseCase.Get(r.Context(), submissionID)
+	if err != nil {
+		if !apperr.IsCode(err, apperr.CodeNotFound) {
+			log.Printf("submission: submission_id=%s failed: %v", submissionID, err)
+		}
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(submissionResponse(sub))
+}
+
+func submissionResponse(sub *entity.Submission) map[string]interface{} {
+	return map[string]interface{}{
+		"submission_id":     sub.SubmissionID.String(),
+		"assignment_id":     sub.AssignmentID,
+		"author_id":         sub.AuthorID,
+		"created_at":        sub.CreatedAt,
+		"status":            sub.Status,
+		"content_type":      sub.ContentType,
+		"original_filename": sub.OriginalFilename,
+	}
+}
+
+func parseTimeParam(w http.ResponseWriter, value, name string) (*time.Time, bool) {
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		respondValidationError(w, name+" must be an RFC 3339 timestamp")
+		return nil, false
+	}
+	return &t, true
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,11 +18,13 @@ var errFileTooLarge = errors.New("file too large")
 
 type SubmitHandler struct {
 	submitUseCase *usecase.SubmitUseCase
+	idempotency   *usecase.IdempotencyUseCase
 }
 
-func NewSubmitHandler(submitUseCase *usecase.SubmitUseCase) *SubmitHandler {
+func NewSubmitHandler(submitUseCase *usecase.SubmitUseCase, idempotency *usecase.IdempotencyUseCase) *SubmitHandler {
 	return &SubmitHandler{
 		submitUseCase: submitUseCase,
+		idempotency:   idempotency,
 	}
 }
 
@@ -31,6 +34,14 @@ func (h *SubmitHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey != "" {
+		if err := usecase.ValidateIdempotencyKey(idempotencyKey); err != nil {
+			respondError(w, err)
+			return
+		}
+	}
+
 	mr, err := r.MultipartReader()
 	if err != nil {
 		respondValidationError(w, "invalid multipart form")
@@ -42,9 +53,8 @@ func (h *SubmitHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	var (
 		assignmentID string
 		login        string
-		fileData     []byte
-		filename     string
-		contentType  string
+		// complete stores the response under the Idempotency-Key, if any.
+		complete = func(int, []byte) {}
 	)
 
 	for {
@@ -76,19 +86,93 @@ func (h *SubmitHandler) Handle(w http.ResponseWriter, r *http.Request) {
 			}
 			login = string(body)
 		case "file":
-			data, readErr := readFilePart(part, maxUploadSize)
-			if readErr != nil {
-				if errors.Is(readErr, errFileTooLarge) {
+			// The file is streamed straight into storage, so the text fields
+			// have to be known by the time it arrives.
+			defer part.Close()
+			if assignmentID == "" {
+				respondValidationError(w, "assignment_id is required and must precede file")
+				return
+			}
+			if login == "" {
+				respondValidationError(w, "login is required and must precede file")
+				return
+			}
+
+			// Keys belong to the author, and a retry has to be for the same
+			// assignment and file name. The file itself is not compared: it
+			// has not been read yet.
+			if idempotencyKey != "" {
+				fingerprint := usecase.Fingerprint(assignmentID, part.FileName())
+				stored, err := h.idempotency.Begin(r.Context(), login, idempotencyKey, fingerprint)
+				if err != nil {
+					respondError(w, err)
+					return
+				}
+				if stored != nil {
+					log.Printf("submit: assignment_id=%s login=%s replayed idempotent response", assignmentID, login)
+					w.Header().Set("Content-Type", "application/json")
+					w.Header().Set("Idempotent-Replayed", "true")
+					w.WriteHeader(stored.StatusCode)
+					_, _ = w.Write(stored.ResponseBody)
+					return
+				}
+				completed := false
+				defer func() {
+					if !completed {
+						h.idempotency.Release(r.Context(), login, idempotencyKey)
+					}
+				}()
+				complete = func(status int, body []byte) {
+					completed = true
+					if err := h.idempotency.Complete(context.WithoutCancel(r.Context()), login, idempotencyKey, status, body); err != nil {
+						log.Printf("submit: assignment_id=%s login=%s failed to store idempotent response: %v", assignmentID, login, err)
+					}
+				}
+			}
+
+			// The type the client claims is ignored; the use case sniffs it
+			// from the content.
+			file := newSizeLimitedReader(part, maxUploadSize)
+			req := dto.SubmitRequest{
+				AssignmentID: assignmentID,
+				Login:        login,
+				File:         file,
+				Filename:     part.FileName(),
+			}
+
+			resp, err := h.submitUseCase.Submit(r.Context(), req)
+			if err != nil {
+				if file.Exceeded() {
 					respondValidationError(w, fmt.Sprintf("file exceeds max size %d bytes", maxUploadSize))
 					return
 				}
-				log.Printf("submit: failed to read file part: %v", readErr)
-				respondValidationError(w, "failed to read file")
+				log.Printf("submit: assignment_id=%s login=%s failed: %v", assignmentID, login, err)
+				respondError(w, err)
 				return
 			}
-			fileData = data
-			filename = part.FileName()
-			contentType = part.Header.Get("Content-Type")
+
+			log.Printf("submit: assignment_id=%s login=%s submission_id=%s size=%d status=%s uploaded", assignmentID, login, resp.SubmissionID, resp.SizeBytes, resp.Status)
+			body := map[string]interface{}{
+				"submission_id": resp.SubmissionID,
+				"size_bytes":    resp.SizeBytes,
+				"checksum":      resp.Checksum,
+				"content_type":  resp.ContentType,
+				"status":        resp.Status,
+			}
+			if resp.Signature != "" {
+				body["signature"] = resp.Signature
+			}
+			encoded, err := json.Marshal(body)
+			if err != nil {
+				respondError(w, err)
+				return
+			}
+			encoded = append(encoded, '\n')
+			complete(http.StatusCreated, encoded)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(encoded)
+			return
 		default:
 			_ = part.Close()
 		}
@@ -104,50 +188,33 @@ func (h *SubmitHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if fileData == nil {
-		respondValidationError(w, "file is required")
-		return
-	}
-
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
-
-	req := dto.SubmitRequest{
-		AssignmentID: assignmentID,
-		Login:        login,
-		Data:         fileData,
-		Filename:     filename,
-		ContentType:  contentType,
-	}
-
-	resp, err := h.submitUseCase.Submit(r.Context(), req)
-	if err != nil {
-		log.Printf("submit: assignment_id=%s login=%s failed: %v", assignmentID, login, err)
-		respondError(w, err)
-		return
-	}
+	respondValidationError(w, "file is required")
+}
 
-	log.Printf("submit: assignment_id=%s login=%s submission_id=%s uploaded", assignmentID, login, resp.SubmissionID)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{
-		"submission_id": resp.SubmissionID,
-	})
+type sizeLimitedReader struct {
+	r        io.Reader
+	max      int64
+	read     int64
+	exceeded bool
 }
 
-func readFilePart(part io.ReadCloser, maxUploadSize int64) ([]byte, error) {
-	defer part.Close()
+func newSizeLimitedReader(r io.Reader, max int64) *sizeLimitedReader {
+	return &sizeLimitedReader{r: r, max: max}
+}
 
-	limited := io.LimitReader(part, maxUploadSize+1)
-	data, err := io.ReadAll(limited)
-	if err != nil {
-		return nil, err
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, errFileTooLarge
 	}
-
-	if int64(len(data)) > maxUploadSize {
-		return nil, errFileTooLarge
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		l.exceeded = true
+		return n, errFileTooLarge
 	}
+	return n, err
+}
 
-	return data, nil
+func (l *sizeLimitedReader) Exceeded() bool {
+	return l.exceeded
 }
@@ -11,17 +11,37 @@ type Router struct {
 	submitHandler      *handler.SubmitHandler
 	submissionsHandler *handler.SubmissionsHandler
 	downloadHandler    *handler.DownloadHandler
+	deleteHandler      *handler.DeleteHandler
+	purgeHandler       *handler.PurgeHandler
+	reconcileHandler   *handler.ReconcileHandler
+	quotaHandler       *handler.QuotaHandler
+	exportHandler      *handler.ExportHandler
+	importHandler      *handler.ImportHandler
 }
 
 func NewRouter(
 	submitUseCase *usecase.SubmitUseCase,
 	getSubmissionsUseCase *usecase.GetSubmissionsUseCase,
 	downloadSubmissionUseCase *usecase.DownloadSubmissionUseCase,
+	deleteSubmissionUseCase *usecase.DeleteSubmissionUseCase,
+	purgeAuthorUseCase *usecase.PurgeAuthorUseCase,
+	reconcileUseCase *usecase.ReconcileUseCase,
+	quotaUseCase *usecase.QuotaUseCase,
+	exportUseCase *usecase.ExportAssignmentUseCase,
+	importUseCase *usecase.ImportUseCase,
+	idempotencyUseCase *usecase.IdempotencyUseCase,
+	adminToken string,
 ) *Router {
 	return &Router{
-		submitHandler:      handler.NewSubmitHandler(submitUseCase),
+		submitHandler:      handler.NewSubmitHandler(submitUseCase, idempotencyUseCase),
 		submissionsHandler: handler.NewSubmissionsHandler(getSubmissionsUseCase),
 		downloadHandler:    handler.NewDownloadHandler(downloadSubmissionUseCase),
+		deleteHandler:      handler.NewDeleteHandler(deleteSubmissionUseCase),
+		purgeHandler:       handler.NewPurgeHandler(purgeAuthorUseCase, adminToken),
+		reconcileHandler:   handler.NewReconcileHandler(reconcileUseCase, adminToken),
+		quotaHandler:       handler.NewQuotaHandler(quotaUseCase),
+		exportHandler:      handler.NewExportHandler(exportUseCase),
+		importHandler:      handler.NewImportHandler(importUseCase, adminToken),
 	}
 }
 
@@ -31,10 +51,25 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux.HandleFunc("/submit", r.submitHandler.Handle)
 	mux.HandleFunc("/submissions", r.submissionsHandler.Handle)
 	mux.HandleFunc("/submissions/download", r.downloadHandler.Handle)
+	mux.HandleFunc("/submissions/download-url", r.downloadHandler.HandleURL)
+	mux.HandleFunc("/submissions/", r.handleSubmission)
+	mux.HandleFunc("/quota", r.quotaHandler.Handle)
+	mux.HandleFunc("/assignments/", r.exportHandler.Handle)
+	mux.HandleFunc("/admin/purge", r.purgeHandler.Handle)
+	mux.HandleFunc("/admin/reconcile", r.reconcileHandler.Handle)
+	mux.HandleFunc("/admin/import", r.importHandler.Handle)
 
 	return corsMiddleware(mux)
 }
 
+func (r *Router) handleSubmission(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		r.submissionsHandler.HandleOne(w, req)
+		return
+	}
+	r.deleteHandler.Handle(w, req)
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
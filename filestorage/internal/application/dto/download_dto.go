@@ -0,0 +1,8 @@
+package dto
+
+import "time"
+
+type DownloadURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type ExportRequest struct {
+	AssignmentID   string
+	IncludeReports bool
+}
+
+// ExportManifest is written to manifest.json at the end of the archive.
+type ExportManifest struct {
+	AssignmentID    string                 `json:"assignment_id"`
+	ExportedAt      time.Time              `json:"exported_at"`
+	ReportsIncluded bool                   `json:"reports_included"`
+	Submissions     []ExportManifestRecord `json:"submissions"`
+}
+
+type ExportManifestRecord struct {
+	SubmissionID     string    `json:"submission_id"`
+	AuthorID         string    `json:"author_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	SizeBytes        int64     `json:"size_bytes"`
+	Checksum         string    `json:"checksum,omitempty"`
+	ContentType      string    `json:"content_type,omitempty"`
+	OriginalFilename string    `json:"original_filename,omitempty"`
+	Status           string    `json:"status"`
+	// Path is the file's name in the archive; empty when it was skipped.
+	Path    string `json:"path,omitempty"`
+	Skipped string `json:"skipped,omitempty"`
+}
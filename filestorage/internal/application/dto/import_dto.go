@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+type ImportRequest struct {
+	AssignmentID string
+	// Format is "moodle" or "csv".
+	Format string
+	// Archive is the uploaded zip, spooled so it can be read at random.
+	Archive     io.ReaderAt
+	ArchiveSize int64
+	// Authors maps Moodle participant ids to author ids. Participants
+	// missing from it keep their participant id.
+	Authors map[string]string
+}
+
+type ImportResponse struct {
+	AssignmentID string               `json:"assignment_id"`
+	Imported     []ImportedSubmission `json:"imported"`
+	Skipped      []SkippedImportEntry `json:"skipped"`
+	// ChecksQueued counts the plagiarism checks released to the outbox,
+	// including ones held back by an earlier, interrupted import.
+	ChecksQueued int64 `json:"checks_queued"`
+}
+
+type ImportedSubmission struct {
+	SubmissionID string    `json:"submission_id"`
+	AuthorID     string    `json:"author_id"`
+	Path         string    `json:"path"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Status       string    `json:"status"`
+}
+
+type SkippedImportEntry struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"filestorage/internal/domain/entity"
+)
+
+type ListSubmissionsRequest struct {
+	AssignmentID  string
+	Limit         int
+	Cursor        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          string
+}
+
+type ListSubmissionsResponse struct {
+	Submissions []*entity.Submission
+	NextCursor  string
+}
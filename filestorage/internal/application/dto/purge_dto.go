@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type PurgeAuthorRequest struct {
+	AuthorID    string
+	RequestedBy string
+}
+
+type PurgeAuthorResponse struct {
+	AuditID         string    `json:"audit_id"`
+	AuthorHash      string    `json:"author_hash"`
+	SubmissionIDs   []string  `json:"submission_ids"`
+	ObjectsDeleted  int       `json:"objects_deleted"`
+	ReportsScrubbed bool      `json:"reports_scrubbed"`
+	RequestedBy     string    `json:"requested_by,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
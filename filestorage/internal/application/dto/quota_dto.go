@@ -0,0 +1,15 @@
+package dto
+
+// QuotaUsageResponse reports an author's usage against the configured
+// limits. A zero limit means unlimited, and the matching remaining field is
+// then omitted.
+type QuotaUsageResponse struct {
+	AuthorID             string `json:"author_id"`
+	AssignmentID         string `json:"assignment_id"`
+	SubmissionsUsed      int64  `json:"submissions_used"`
+	SubmissionsLimit     int64  `json:"submissions_limit"`
+	SubmissionsRemaining *int64 `json:"submissions_remaining,omitempty"`
+	BytesUsed            int64  `json:"bytes_used"`
+	BytesLimit           int64  `json:"bytes_limit"`
+	BytesRemaining       *int64 `json:"bytes_remaining,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+const (
+	ReconcileIssueOrphanObject = "orphan_object"
+	ReconcileIssueMissingFile  = "missing_file"
+	ReconcileIssueFileRestored = "file_restored"
+)
+
+type ReconcileIssue struct {
+	Kind     string `json:"kind"`
+	Key      string `json:"key"`
+	Repaired bool   `json:"repaired"`
+}
+
+type ReconcileReport struct {
+	StartedAt      time.Time        `json:"started_at"`
+	FinishedAt     time.Time        `json:"finished_at"`
+	Repair         bool             `json:"repair"`
+	ObjectsScanned int              `json:"objects_scanned"`
+	RowsScanned    int              `json:"rows_scanned"`
+	Issues         []ReconcileIssue `json:"issues"`
+}
@@ -1,13 +1,26 @@
 package dto
 
+import (
+	"io"
+	"time"
+)
+
 type SubmitRequest struct {
 	AssignmentID string
 	Login        string
-	Data         []byte
+	File         io.Reader
 	Filename     string
-	ContentType  string
+	// SubmittedAt overrides the creation time for imported submissions; zero
+	// means now.
+	SubmittedAt time.Time
 }
 
 type SubmitResponse struct {
 	SubmissionID string
+	SizeBytes    int64
+	Checksum     string
+	ContentType  string
+	Status       string
+	// Signature is what the malware scanner found, if anything.
+	Signature string
 }
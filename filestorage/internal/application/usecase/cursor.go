@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+type cursorPayload struct {
+	CreatedAt    time.Time `json:"created_at"`
+	SubmissionID uuid.UUID `json:"submission_id"`
+	Sort         string    `json:"sort"`
+}
+
+func encodeCursor(sub *entity.Submission, sort repository.SortOrder) string {
+	data, _ := json.Marshal(cursorPayload{
+		CreatedAt:    sub.CreatedAt,
+		SubmissionID: sub.SubmissionID,
+		Sort:         string(sort),
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string, sort repository.SortOrder) (*repository.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, errInvalidCursor
+	}
+	if payload.SubmissionID == uuid.Nil || payload.CreatedAt.IsZero() || payload.Sort != string(sort) {
+		return nil, errInvalidCursor
+	}
+
+	return &repository.Cursor{
+		CreatedAt:    payload.CreatedAt.UTC(),
+		SubmissionID: payload.SubmissionID,
+	}, nil
+}
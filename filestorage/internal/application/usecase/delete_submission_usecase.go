@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+type DeleteSubmissionUseCase struct {
+	submissionRepo repository.SubmissionRepository
+	s3Repo         repository.S3Repository
+	outbox         repository.OutboxRepository
+	events         *OutboxDispatcher
+}
+
+// NewDeleteSubmissionUseCase builds the deletion. Plagiarism learns about it
+// from a submission_deleted event committed together with the deletion;
+// events, if not nil, is woken up to deliver it.
+func NewDeleteSubmissionUseCase(
+	submissionRepo repository.SubmissionRepository,
+	s3Repo repository.S3Repository,
+	outbox repository.OutboxRepository,
+	events *OutboxDispatcher,
+) *DeleteSubmissionUseCase {
+	return &DeleteSubmissionUseCase{
+		submissionRepo: submissionRepo,
+		s3Repo:         s3Repo,
+		outbox:         outbox,
+		events:         events,
+	}
+}
+
+// Delete hides the submission from reads first and only then removes the
+// object, so a failure half-way leaves an orphaned object rather than a row
+// pointing at nothing; the error tells the caller to repeat the request,
+// which finishes whatever was left undone. Reports are dropped by the outbox
+// once the row is hidden, however the rest goes.
+func (uc *DeleteSubmissionUseCase) Delete(ctx context.Context, submissionID, assignmentID string) error {
+	if submissionID == "" {
+		return newValidationError("submission_id is required")
+	}
+
+	id, err := uuid.Parse(submissionID)
+	if err != nil {
+		return newValidationError("invalid submission_id")
+	}
+
+	submission, tx, err := uc.submissionRepo.SoftDeleteWithTx(ctx, id, assignmentID)
+	if err != nil {
+		if apperr.IsCode(err, apperr.CodeNotFound) {
+			return err
+		}
+		return wrapDatabaseError(err, "failed to delete submission")
+	}
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	now := time.Now()
+	event := &entity.OutboxEvent{
+		Type:         entity.OutboxEventSubmissionDeleted,
+		AssignmentID: submission.AssignmentID,
+		SubmissionID: submission.SubmissionID,
+		AvailableAt:  &now,
+	}
+	if err := uc.outbox.AddWithTx(ctx, tx, event); err != nil {
+		return wrapDatabaseError(err, "failed to store submission event")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return wrapDatabaseError(err, "failed to commit delete tx")
+	}
+	tx = nil
+	uc.events.Notify()
+
+	if err := uc.s3Repo.DeleteFile(ctx, submission.SubmissionID.String()); err != nil {
+		return wrapStorageError(err, "failed to delete submission file")
+	}
+
+	return nil
+}
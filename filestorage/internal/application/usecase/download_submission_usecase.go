@@ -2,38 +2,125 @@ package usecase
 
 import (
 	"context"
-	"errors"
 	"io"
+	"time"
 
+	"filestorage/internal/application/dto"
 	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
 	"filestorage/internal/domain/repository"
 
-	"github.com/aws/smithy-go"
 	"github.com/google/uuid"
 )
 
 type DownloadSubmissionUseCase struct {
 	submissionRepo repository.SubmissionRepository
 	s3Repo         repository.S3Repository
+	presigner      repository.Presigner
+	presignTTL     time.Duration
 }
 
 type DownloadSubmissionResponse struct {
 	File        io.ReadCloser
 	Filename    string
 	ContentType string
+	// Size and ETag (the stored SHA-256) are zero for rows uploaded before
+	// they were tracked.
+	Size         int64
+	ETag         string
+	LastModified time.Time
+
+	key string
 }
 
 func NewDownloadSubmissionUseCase(
 	submissionRepo repository.SubmissionRepository,
 	s3Repo repository.S3Repository,
+	presigner repository.Presigner,
+	presignTTL time.Duration,
 ) *DownloadSubmissionUseCase {
 	return &DownloadSubmissionUseCase{
 		submissionRepo: submissionRepo,
 		s3Repo:         s3Repo,
+		presigner:      presigner,
+		presignTTL:     presignTTL,
+	}
+}
+
+// Stat resolves the submission without touching the object store, so that
+// conditional requests can be answered before any bytes are fetched. The
+// returned File is nil until Open is called.
+func (uc *DownloadSubmissionUseCase) Stat(ctx context.Context, submissionID string) (*DownloadSubmissionResponse, error) {
+	submission, err := uc.getSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DownloadSubmissionResponse{
+		Filename:     submission.SubmissionID.String(),
+		ContentType:  "application/octet-stream",
+		Size:         submission.SizeBytes,
+		ETag:         submission.Checksum,
+		LastModified: submission.CreatedAt,
+		key:          submission.SubmissionID.String(),
+	}, nil
+}
+
+// Open fetches length bytes of the file starting at offset into resp.File;
+// a negative length reads the whole remainder.
+func (uc *DownloadSubmissionUseCase) Open(ctx context.Context, resp *DownloadSubmissionResponse, offset, length int64) error {
+	var (
+		file io.ReadCloser
+		err  error
+	)
+	if offset == 0 && length < 0 {
+		file, err = uc.s3Repo.GetFile(ctx, resp.key)
+	} else {
+		file, err = uc.s3Repo.GetFileRange(ctx, resp.key, offset, length)
+	}
+	if err != nil {
+		if apperr.IsCode(err, apperr.CodeNotFound) {
+			return wrapNotFoundError(err, "submission file not found")
+		}
+		return wrapStorageError(err, "failed to get submission file")
+	}
+
+	resp.File = file
+	return nil
+}
+
+// DownloadURL returns a short-lived URL to fetch the file straight from the
+// object store. Backends that cannot presign, or PRESIGN_TTL=0, yield an
+// unsupported error and callers fall back to Download.
+func (uc *DownloadSubmissionUseCase) DownloadURL(ctx context.Context, submissionID string) (*dto.DownloadURLResponse, error) {
+	if uc.presigner == nil || uc.presignTTL <= 0 {
+		return nil, apperr.New(apperr.CodeUnsupported, "presigned downloads are not available")
+	}
+
+	submission, err := uc.getSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	// Presigning does not touch the store, so a known-missing file has to be
+	// rejected here rather than surface as a broken link.
+	if submission.Status == entity.SubmissionStatusFileMissing {
+		return nil, apperr.New(apperr.CodeNotFound, "submission file not found")
+	}
+
+	key := submission.SubmissionID.String()
+	expiresAt := time.Now().UTC().Add(uc.presignTTL)
+	url, err := uc.presigner.PresignGetFile(ctx, key, key, uc.presignTTL)
+	if err != nil {
+		return nil, wrapStorageError(err, "failed to presign submission file")
 	}
+
+	return &dto.DownloadURLResponse{
+		URL:       url,
+		ExpiresAt: expiresAt,
+	}, nil
 }
 
-func (uc *DownloadSubmissionUseCase) Download(ctx context.Context, submissionID string) (*DownloadSubmissionResponse, error) {
+func (uc *DownloadSubmissionUseCase) getSubmission(ctx context.Context, submissionID string) (*entity.Submission, error) {
 	if submissionID == "" {
 		return nil, newValidationError("submission_id is required")
 	}
@@ -50,19 +137,8 @@ func (uc *DownloadSubmissionUseCase) Download(ctx context.Context, submissionID
 		}
 		return nil, wrapDatabaseError(err, "failed to get submission")
 	}
-
-	file, err := uc.s3Repo.GetFile(ctx, submission.SubmissionID.String())
-	if err != nil {
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey" {
-			return nil, wrapNotFoundError(err, "submission file not found")
-		}
-		return nil, wrapStorageError(err, "failed to get submission file")
+	if submission.Status == entity.SubmissionStatusQuarantined {
+		return nil, apperr.New(apperr.CodeForbidden, "submission is quarantined")
 	}
-
-	return &DownloadSubmissionResponse{
-		File:        file,
-		Filename:    submission.SubmissionID.String(),
-		ContentType: "application/octet-stream",
-	}, nil
+	return submission, nil
 }
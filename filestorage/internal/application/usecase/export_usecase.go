@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"filestorage/internal/application/dto"
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+)
+
+const exportPageSize = 500
+
+const (
+	exportSkippedQuarantined = "quarantined"
+	exportSkippedFileMissing = "file_missing"
+)
+
+// ReportsFetcher returns the plagiarism reports of an assignment as the
+// plagiarism service renders them.
+type ReportsFetcher interface {
+	WorkReports(ctx context.Context, assignmentID string) ([]byte, error)
+}
+
+type ExportAssignmentUseCase struct {
+	submissionRepo repository.SubmissionRepository
+	s3Repo         repository.S3Repository
+	reports        ReportsFetcher
+}
+
+// NewExportAssignmentUseCase builds the assignment export. reports may be
+// nil, in which case exports cannot include plagiarism reports.
+func NewExportAssignmentUseCase(
+	submissionRepo repository.SubmissionRepository,
+	s3Repo repository.S3Repository,
+	reports ReportsFetcher,
+) *ExportAssignmentUseCase {
+	return &ExportAssignmentUseCase{
+		submissionRepo: submissionRepo,
+		s3Repo:         s3Repo,
+		reports:        reports,
+	}
+}
+
+// AssignmentExport is an export that has been checked and can be written.
+type AssignmentExport struct {
+	AssignmentID string
+
+	firstPage []*entity.Submission
+	reports   []byte
+}
+
+// Prepare does everything that can fail cleanly before the archive is
+// streamed: once Write has started, the response status is already sent.
+func (uc *ExportAssignmentUseCase) Prepare(ctx context.Context, req dto.ExportRequest) (*AssignmentExport, error) {
+	if req.AssignmentID == "" {
+		return nil, newValidationError("assignment_id is required")
+	}
+	if req.IncludeReports && uc.reports == nil {
+		return nil, apperr.New(apperr.CodeUnsupported, "plagiarism reports are not available")
+	}
+
+	page, err := uc.listPage(ctx, req.AssignmentID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(page) == 0 {
+		return nil, apperr.New(apperr.CodeNotFound, "assignment has no submissions")
+	}
+
+	export := &AssignmentExport{AssignmentID: req.AssignmentID, firstPage: page}
+	if req.IncludeReports {
+		export.reports, err = uc.reports.WorkReports(ctx, req.AssignmentID)
+		if err != nil {
+			return nil, apperr.Wrap(err, apperr.CodeUnavailable, "failed to fetch plagiarism reports")
+		}
+	}
+	return export, nil
+}
+
+// Write streams the archive: each file under author_id/original_filename,
+// then reports.json if requested and manifest.json describing it all.
+// Quarantined and missing files are listed in the manifest but left out.
+func (uc *ExportAssignmentUseCase) Write(ctx context.Context, export *AssignmentExport, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	manifest := dto.ExportManifest{
+		AssignmentID:    export.AssignmentID,
+		ExportedAt:      time.Now().UTC(),
+		ReportsIncluded: export.reports != nil,
+		Submissions:     make([]dto.ExportManifestRecord, 0, len(export.firstPage)),
+	}
+	paths := make(map[string]bool)
+
+	page := export.firstPage
+	for len(page) > 0 {
+		for _, sub := range page {
+			record, err := uc.writeSubmission(ctx, zw, sub, paths)
+			if err != nil {
+				return err
+			}
+			manifest.Submissions = append(manifest.Submissions, record)
+		}
+		if len(page) < exportPageSize {
+			break
+		}
+
+		last := page[len(page)-1]
+		var err error
+		page, err = uc.listPage(ctx, export.AssignmentID, &repository.Cursor{CreatedAt: last.CreatedAt, SubmissionID: last.SubmissionID})
+		if err != nil {
+			return err
+		}
+	}
+
+	if export.reports != nil {
+		if err := writeZipJSON(zw, "reports.json", json.RawMessage(export.reports)); err != nil {
+			return err
+		}
+	}
+	if err := writeZipJSON(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func (uc *ExportAssignmentUseCase) listPage(ctx context.Context, assignmentID string, after *repository.Cursor) ([]*entity.Submission, error) {
+	page, err := uc.submissionRepo.ListByAssignmentID(ctx, repository.ListFilter{
+		AssignmentID: assignmentID,
+		Sort:         repository.SortCreatedAtAsc,
+		After:        after,
+		Limit:        exportPageSize,
+	})
+	if err != nil {
+		return nil, wrapDatabaseError(err, "failed to list submissions")
+	}
+	return page, nil
+}
+
+func (uc *ExportAssignmentUseCase) writeSubmission(ctx context.Context, zw *zip.Writer, sub *entity.Submission, paths map[string]bool) (dto.ExportManifestRecord, error) {
+	record := dto.ExportManifestRecord{
+		SubmissionID:     sub.SubmissionID.String(),
+		AuthorID:         sub.AuthorID,
+		CreatedAt:        sub.CreatedAt,
+		SizeBytes:        sub.SizeBytes,
+		Checksum:         sub.Checksum,
+		ContentType:      sub.ContentType,
+		OriginalFilename: sub.OriginalFilename,
+		Status:           string(sub.Status),
+	}
+
+	switch sub.Status {
+	case entity.SubmissionStatusQuarantined:
+		record.Skipped = exportSkippedQuarantined
+		return record, nil
+	case entity.SubmissionStatusFileMissing:
+		record.Skipped = exportSkippedFileMissing
+		return record, nil
+	}
+
+	file, err := uc.s3Repo.GetFile(ctx, sub.SubmissionID.String())
+	if err != nil {
+		if apperr.IsCode(err, apperr.CodeNotFound) {
+			log.Printf("export: assignment_id=%s submission_id=%s file not found, skipping", sub.AssignmentID, record.SubmissionID)
+			record.Skipped = exportSkippedFileMissing
+			return record, nil
+		}
+		return record, wrapStorageError(err, "failed to get submission file")
+	}
+	defer file.Close()
+
+	record.Path = exportPath(sub, paths)
+	entry, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     record.Path,
+		Method:   zip.Deflate,
+		Modified: sub.CreatedAt,
+	})
+	if err != nil {
+		return record, err
+	}
+	if _, err := io.Copy(entry, file); err != nil {
+		return record, wrapStorageError(err, "failed to read submission file")
+	}
+	return record, nil
+}
+
+// exportPath picks a unique author_id/filename path. Names are reduced to a
+// single safe path element, and repeats get the submission id appended.
+func exportPath(sub *entity.Submission, used map[string]bool) string {
+	dir := exportPathElement(sub.AuthorID, "unknown")
+	name := exportPathElement(sub.OriginalFilename, sub.SubmissionID.String())
+
+	p := dir + "/" + name
+	if used[p] {
+		ext := path.Ext(name)
+		p = fmt.Sprintf("%s/%s_%s%s", dir, strings.TrimSuffix(name, ext), sub.SubmissionID, ext)
+	}
+	used[p] = true
+	return p
+}
+
+func exportPathElement(s, fallback string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, s)
+	s = strings.TrimSpace(s)
+	if s == "" || s == "." || s == ".." {
+		return fallback
+	}
+	return s
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	entry, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(entry)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -2,9 +2,19 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
+	"filestorage/internal/application/dto"
+	apperr "filestorage/internal/common/errors"
 	"filestorage/internal/domain/entity"
 	"filestorage/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
 )
 
 type GetSubmissionsUseCase struct {
@@ -17,11 +27,79 @@ func NewGetSubmissionsUseCase(submissionRepo repository.SubmissionRepository) *G
 	}
 }
 
-func (uc *GetSubmissionsUseCase) GetByAssignmentID(ctx context.Context, assignmentID string) ([]*entity.Submission, error) {
-	submissions, err := uc.submissionRepo.GetByAssignmentID(ctx, assignmentID)
+func (uc *GetSubmissionsUseCase) List(ctx context.Context, req dto.ListSubmissionsRequest) (*dto.ListSubmissionsResponse, error) {
+	if req.AssignmentID == "" {
+		return nil, newValidationError("assignment_id is required")
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit < 1 || limit > maxPageSize {
+		return nil, newValidationError(fmt.Sprintf("limit must be between 1 and %d", maxPageSize))
+	}
+
+	sort := repository.SortOrder(req.Sort)
+	switch sort {
+	case "":
+		sort = repository.SortCreatedAtDesc
+	case repository.SortCreatedAtDesc, repository.SortCreatedAtAsc:
+	default:
+		return nil, newValidationError("sort must be one of created_at_desc, created_at_asc")
+	}
+
+	filter := repository.ListFilter{
+		AssignmentID: req.AssignmentID,
+		Sort:         sort,
+		Limit:        limit + 1,
+	}
+	if req.CreatedAfter != nil {
+		after := req.CreatedAfter.UTC()
+		filter.CreatedAfter = &after
+	}
+	if req.CreatedBefore != nil {
+		before := req.CreatedBefore.UTC()
+		filter.CreatedBefore = &before
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return nil, newValidationError("created_after must be earlier than created_before")
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor, sort)
+		if err != nil {
+			return nil, newValidationError("invalid cursor")
+		}
+		filter.After = cursor
+	}
+
+	submissions, err := uc.submissionRepo.ListByAssignmentID(ctx, filter)
 	if err != nil {
 		return nil, wrapDatabaseError(err, "failed to fetch submissions")
 	}
 
-	return submissions, nil
+	resp := &dto.ListSubmissionsResponse{Submissions: submissions}
+	if len(submissions) > limit {
+		resp.Submissions = submissions[:limit]
+		resp.NextCursor = encodeCursor(resp.Submissions[limit-1], sort)
+	}
+
+	return resp, nil
+}
+
+func (uc *GetSubmissionsUseCase) Get(ctx context.Context, submissionID string) (*entity.Submission, error) {
+	id, err := uuid.Parse(submissionID)
+	if err != nil {
+		return nil, newValidationError("invalid submission_id")
+	}
+
+	submission, err := uc.submissionRepo.GetByID(ctx, id)
+	if err != nil {
+		if apperr.IsCode(err, apperr.CodeNotFound) {
+			return nil, err
+		}
+		return nil, wrapDatabaseError(err, "failed to get submission")
+	}
+	return submission, nil
 }
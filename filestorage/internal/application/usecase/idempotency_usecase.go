@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+)
+
+// MaxIdempotencyKeyLength bounds the Idempotency-Key header.
+const MaxIdempotencyKeyLength = 255
+
+// idempotencyLockTimeout is how long a key may stay in progress before the
+// request holding it is taken for dead, e.g. after a crash, and a retry may
+// run the request again.
+const idempotencyLockTimeout = 5 * time.Minute
+
+var (
+	ErrIdempotencyInProgress = apperr.New(apperr.CodeConflict, "a request with this Idempotency-Key is still in progress")
+	ErrIdempotencyKeyReused  = apperr.New(apperr.CodeConflict, "Idempotency-Key was already used for a different request")
+)
+
+// IdempotencyUseCase lets retries of a request made with an Idempotency-Key
+// get the response of the first attempt instead of repeating it.
+type IdempotencyUseCase struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func NewIdempotencyUseCase(repo repository.IdempotencyRepository, ttl time.Duration) *IdempotencyUseCase {
+	return &IdempotencyUseCase{repo: repo, ttl: ttl, now: time.Now}
+}
+
+// ValidateIdempotencyKey checks the header value a client sent.
+func ValidateIdempotencyKey(key string) error {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return newValidationError("Idempotency-Key must be 1 to 255 characters long")
+	}
+	for _, r := range key {
+		if r < 0x21 || r > 0x7e {
+			return newValidationError("Idempotency-Key must be printable ASCII without spaces")
+		}
+	}
+	return nil
+}
+
+// Fingerprint identifies a request by the parts that must match when its
+// key is reused.
+func Fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Begin reserves key for a new request. It returns the stored response when
+// the key has already completed a request with the same fingerprint, and
+// nil when the caller should go on and later call Complete or Release.
+func (uc *IdempotencyUseCase) Begin(ctx context.Context, scope, key, fingerprint string) (*entity.IdempotencyKey, error) {
+	now := uc.now().UTC()
+	for range 2 {
+		stored, reserved, err := uc.repo.Reserve(ctx, &entity.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		switch {
+		case stored.CreatedAt.Before(now.Add(-uc.ttl)):
+			if _, err := uc.repo.DeleteExpired(ctx, now.Add(-uc.ttl)); err != nil {
+				return nil, err
+			}
+		case stored.InProgress() && stored.CreatedAt.Before(now.Add(-idempotencyLockTimeout)):
+			if err := uc.repo.Release(ctx, scope, key); err != nil {
+				return nil, err
+			}
+		case stored.Fingerprint != fingerprint:
+			return nil, ErrIdempotencyKeyReused
+		case stored.InProgress():
+			return nil, ErrIdempotencyInProgress
+		default:
+			return stored, nil
+		}
+	}
+	return nil, ErrIdempotencyInProgress
+}
+
+// Complete stores the response to replay for the key.
+func (uc *IdempotencyUseCase) Complete(ctx context.Context, scope, key string, statusCode int, body []byte) error {
+	return uc.repo.Complete(ctx, scope, key, statusCode, body)
+}
+
+// Release frees the key of a request that failed, so that a retry runs it
+// again. It outlives the request's context.
+func (uc *IdempotencyUseCase) Release(ctx context.Context, scope, key string) {
+	if err := uc.repo.Release(context.WithoutCancel(ctx), scope, key); err != nil {
+		log.Printf("idempotency: failed to release key scope=%s: %v", scope, err)
+	}
+}
+
+// RunPeriodically deletes expired keys every interval until ctx is
+// cancelled.
+func (uc *IdempotencyUseCase) RunPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := uc.repo.DeleteExpired(ctx, uc.now().UTC().Add(-uc.ttl))
+			if err != nil {
+				log.Printf("idempotency: failed to delete expired keys: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("idempotency: deleted %d expired keys", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"filestorage/internal/application/dto"
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/repository"
+)
+
+const (
+	ImportFormatMoodle = "moodle"
+	ImportFormatCSV    = "csv"
+)
+
+// importManifestName is the index of a csv import, at the archive root.
+const importManifestName = "submissions.csv"
+
+// moodleEntry matches the per-student folder of a Moodle assignment download,
+// "Full Name_12345_assignsubmission_file_", or the same prefix on the file
+// itself when the download was not split into folders.
+var moodleEntry = regexp.MustCompile(`^(.+)_(\d+)_assignsubmission_[a-z]+_(.*)$`)
+
+type ImportUseCase struct {
+	submit         *SubmitUseCase
+	submissionRepo repository.SubmissionRepository
+	outbox         repository.OutboxRepository
+	maxFileSize    int64
+}
+
+// NewImportUseCase builds the bulk import. Files go through the same upload
+// path as regular submissions, type checks and malware scan included, but
+// quotas do not apply.
+func NewImportUseCase(
+	submit *SubmitUseCase,
+	submissionRepo repository.SubmissionRepository,
+	outbox repository.OutboxRepository,
+	maxFileSize int64,
+) *ImportUseCase {
+	return &ImportUseCase{
+		submit:         submit,
+		submissionRepo: submissionRepo,
+		outbox:         outbox,
+		maxFileSize:    maxFileSize,
+	}
+}
+
+// importEntry is one file of the archive and who submitted it when.
+type importEntry struct {
+	file        *zip.File
+	authorID    string
+	filename    string
+	submittedAt time.Time
+}
+
+// Import creates a submission for every file of the archive and then starts
+// plagiarism checks for the whole batch at once: the check events are held
+// in the outbox until every file is in. Files already present for the same
+// author with the same content are skipped, so a failed import can simply
+// be run again, which also releases the events it left held.
+func (uc *ImportUseCase) Import(ctx context.Context, req dto.ImportRequest) (*dto.ImportResponse, error) {
+	if req.AssignmentID == "" {
+		return nil, newValidationError("assignment_id is required")
+	}
+
+	archive, err := zip.NewReader(req.Archive, req.ArchiveSize)
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("malformed zip archive: %v", err))
+	}
+
+	resp := &dto.ImportResponse{
+		AssignmentID: req.AssignmentID,
+		Imported:     []dto.ImportedSubmission{},
+		Skipped:      []dto.SkippedImportEntry{},
+	}
+
+	var entries []importEntry
+	switch req.Format {
+	case ImportFormatMoodle:
+		entries = moodleEntries(archive, req.Authors, resp)
+	case ImportFormatCSV:
+		entries, err = csvEntries(archive, resp)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, newValidationError(fmt.Sprintf("unknown import format %q (available: %s, %s)", req.Format, ImportFormatMoodle, ImportFormatCSV))
+	}
+
+	existing, err := uc.existingFiles(ctx, req.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		imported, reason, err := uc.importEntry(ctx, req.AssignmentID, entry, existing)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			resp.Skipped = append(resp.Skipped, dto.SkippedImportEntry{Path: entry.file.Name, Reason: reason})
+			continue
+		}
+		resp.Imported = append(resp.Imported, *imported)
+	}
+
+	queued, err := uc.outbox.Release(ctx, req.AssignmentID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	uc.submit.events.Notify()
+	resp.ChecksQueued = queued
+
+	log.Printf("import: assignment_id=%s format=%s imported=%d skipped=%d checks_queued=%d", req.AssignmentID, req.Format, len(resp.Imported), len(resp.Skipped), queued)
+	return resp, nil
+}
+
+// importEntry stores one file. A file that cannot be imported is reported
+// with a reason; only a cancelled request aborts the whole import.
+func (uc *ImportUseCase) importEntry(ctx context.Context, assignmentID string, entry importEntry, existing map[string]bool) (*dto.ImportedSubmission, string, error) {
+	if entry.file.UncompressedSize64 > uint64(uc.maxFileSize) {
+		return nil, fmt.Sprintf("file exceeds max size %d bytes", uc.maxFileSize), nil
+	}
+
+	checksum, err := zipFileChecksum(entry.file)
+	if err != nil {
+		return nil, fmt.Sprintf("failed to read file: %v", err), nil
+	}
+	key := entry.authorID + "\x00" + checksum
+	if existing[key] {
+		return nil, "already imported", nil
+	}
+
+	rc, err := entry.file.Open()
+	if err != nil {
+		return nil, fmt.Sprintf("failed to read file: %v", err), nil
+	}
+	defer rc.Close()
+
+	resp, err := uc.submit.store(ctx, dto.SubmitRequest{
+		AssignmentID: assignmentID,
+		Login:        entry.authorID,
+		File:         rc,
+		Filename:     entry.filename,
+		SubmittedAt:  entry.submittedAt,
+	}, -1, true)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, "", ctxErr
+		}
+		log.Printf("import: assignment_id=%s entry %q failed: %v", assignmentID, entry.file.Name, err)
+		return nil, apperr.Message(err), nil
+	}
+	existing[key] = true
+
+	return &dto.ImportedSubmission{
+		SubmissionID: resp.SubmissionID,
+		AuthorID:     entry.authorID,
+		Path:         entry.file.Name,
+		SubmittedAt:  entry.submittedAt.UTC(),
+		SizeBytes:    resp.SizeBytes,
+		Status:       resp.Status,
+	}, "", nil
+}
+
+// existingFiles returns author/checksum keys of the files the assignment
+// already has.
+func (uc *ImportUseCase) existingFiles(ctx context.Context, assignmentID string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	var after *repository.Cursor
+	for {
+		page, err := uc.submissionRepo.ListByAssignmentID(ctx, repository.ListFilter{
+			AssignmentID: assignmentID,
+			Sort:         repository.SortCreatedAtAsc,
+			After:        after,
+			Limit:        exportPageSize,
+		})
+		if err != nil {
+			return nil, wrapDatabaseError(err, "failed to list submissions")
+		}
+		for _, sub := range page {
+			if sub.Checksum != "" {
+				existing[sub.AuthorID+"\x00"+sub.Checksum] = true
+			}
+		}
+		if len(page) < exportPageSize {
+			return existing, nil
+		}
+		last := page[len(page)-1]
+		after = &repository.Cursor{CreatedAt: last.CreatedAt, SubmissionID: last.SubmissionID}
+	}
+}
+
+func moodleEntries(archive *zip.Reader, authors map[string]string, resp *dto.ImportResponse) []importEntry {
+	var entries []importEntry
+	for _, f := range archive.File {
+		if skipArchiveFile(f) {
+			continue
+		}
+
+		first, rest, nested := strings.Cut(f.Name, "/")
+		m := moodleEntry.FindStringSubmatch(first)
+		if m == nil || (!nested && m[3] == "") {
+			resp.Skipped = append(resp.Skipped, dto.SkippedImportEntry{Path: f.Name, Reason: "not a Moodle submission file"})
+			continue
+		}
+
+		filename := m[3]
+		if nested {
+			filename = path.Base(rest)
+		}
+		authorID := m[2]
+		if mapped := authors[m[2]]; mapped != "" {
+			authorID = mapped
+		}
+
+		entries = append(entries, importEntry{
+			file:        f,
+			authorID:    authorID,
+			filename:    filename,
+			submittedAt: zipFileTime(f),
+		})
+	}
+	return entries
+}
+
+// csvEntries reads submissions.csv: a header naming author_id and filename
+// columns, optionally submitted_at (RFC 3339), then one row per file. Files
+// without a row are not imported.
+func csvEntries(archive *zip.Reader, resp *dto.ImportResponse) ([]importEntry, error) {
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		if !skipArchiveFile(f) {
+			files[f.Name] = f
+		}
+	}
+
+	manifest, ok := files[importManifestName]
+	if !ok {
+		return nil, newValidationError(importManifestName + " is missing from the archive root")
+	}
+	delete(files, importManifestName)
+
+	rc, err := manifest.Open()
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("failed to read %s: %v", importManifestName, err))
+	}
+	defer rc.Close()
+
+	r := csv.NewReader(rc)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("malformed %s: %v", importManifestName, err))
+	}
+	if len(rows) == 0 {
+		return nil, newValidationError(importManifestName + " is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"author_id", "filename"} {
+		if _, ok := columns[required]; !ok {
+			return nil, newValidationError(fmt.Sprintf("%s has no %s column", importManifestName, required))
+		}
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var entries []importEntry
+	listed := make(map[string]bool)
+	for n, row := range rows[1:] {
+		line := fmt.Sprintf("%s:%d", importManifestName, n+2)
+		authorID, filename := field(row, "author_id"), path.Clean(field(row, "filename"))
+		if authorID == "" || filename == "." {
+			resp.Skipped = append(resp.Skipped, dto.SkippedImportEntry{Path: line, Reason: "author_id and filename are required"})
+			continue
+		}
+		f, ok := files[filename]
+		if !ok {
+			resp.Skipped = append(resp.Skipped, dto.SkippedImportEntry{Path: line, Reason: fmt.Sprintf("file %q is not in the archive", filename)})
+			continue
+		}
+
+		submittedAt := zipFileTime(f)
+		if raw := field(row, "submitted_at"); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				resp.Skipped = append(resp.Skipped, dto.SkippedImportEntry{Path: line, Reason: fmt.Sprintf("submitted_at %q is not an RFC 3339 time", raw)})
+				continue
+			}
+			submittedAt = t
+		}
+
+		listed[filename] = true
+		entries = append(entries, importEntry{
+			file:        f,
+			authorID:    authorID,
+			filename:    path.Base(filename),
+			submittedAt: submittedAt,
+		})
+	}
+
+	for _, f := range archive.File {
+		if files[f.Name] == f && !listed[f.Name] {
+			resp.Skipped = append(resp.Skipped, dto.SkippedImportEntry{Path: f.Name, Reason: "not listed in " + importManifestName})
+		}
+	}
+	return entries, nil
+}
+
+// skipArchiveFile filters directories and the metadata archivers add.
+func skipArchiveFile(f *zip.File) bool {
+	if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+		return true
+	}
+	return strings.HasPrefix(path.Base(f.Name), ".")
+}
+
+// zipFileTime is the entry's modification time, which LMS exports set to
+// the submission time.
+func zipFileTime(f *zip.File) time.Time {
+	if f.Modified.IsZero() {
+		return time.Now()
+	}
+	return f.Modified
+}
+
+func zipFileChecksum(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ParseImportAuthors reads a participant_id,author_id CSV mapping Moodle
+// participants to author ids. A header row is allowed.
+func ParseImportAuthors(r io.Reader) (map[string]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, newValidationError(fmt.Sprintf("malformed authors mapping: %v", err))
+	}
+
+	authors := make(map[string]string, len(rows))
+	for i, row := range rows {
+		participantID, authorID := strings.TrimSpace(row[0]), strings.TrimSpace(row[1])
+		if i == 0 && !digitsOnly(participantID) {
+			continue
+		}
+		if !digitsOnly(participantID) || authorID == "" {
+			return nil, newValidationError(fmt.Sprintf("authors mapping line %d: expected participant_id,author_id", i+1))
+		}
+		authors[participantID] = authorID
+	}
+	return authors, nil
+}
+
+func digitsOnly(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
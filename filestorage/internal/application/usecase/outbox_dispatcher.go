@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+)
+
+const (
+	outboxBatchSize = 500
+	// outboxLease hides claimed events from other dispatchers while they are
+	// being delivered; it must outlast a delivery attempt.
+	outboxLease = time.Minute
+	// outboxMinBackoff is the delay after the first failed attempt; it
+	// doubles with every further attempt.
+	outboxMinBackoff = time.Second
+)
+
+// CheckStarter queues plagiarism checks for a batch of submissions of one
+// assignment. Starting a check that was already started must be a no-op,
+// since an event is delivered again whenever its outcome is unknown.
+type CheckStarter interface {
+	StartChecks(ctx context.Context, assignmentID string, submissionIDs []string) error
+}
+
+// SubmissionDeletionNotifier drops plagiarism's reports of a deleted
+// submission. Dropping them twice must be a no-op.
+type SubmissionDeletionNotifier interface {
+	SubmissionDeleted(ctx context.Context, assignmentID, submissionID string) error
+}
+
+// OutboxDispatcher delivers outbox events until delivery succeeds.
+type OutboxDispatcher struct {
+	outbox     repository.OutboxRepository
+	checks     CheckStarter
+	deletions  SubmissionDeletionNotifier
+	interval   time.Duration
+	maxBackoff time.Duration
+	wake       chan struct{}
+}
+
+func NewOutboxDispatcher(
+	outbox repository.OutboxRepository,
+	checks CheckStarter,
+	deletions SubmissionDeletionNotifier,
+	interval time.Duration,
+	maxBackoff time.Duration,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outbox:     outbox,
+		checks:     checks,
+		deletions:  deletions,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Notify wakes the dispatcher after new events were committed, so they do
+// not wait for the next poll. It never blocks and is safe on a nil
+// dispatcher.
+func (d *OutboxDispatcher) Notify() {
+	if d == nil {
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due events until ctx is cancelled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			delivered, err := d.DispatchOnce(ctx)
+			if err != nil {
+				log.Printf("outbox: dispatch failed: %v", err)
+				break
+			}
+			if delivered < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// DispatchOnce claims one batch of due events and delivers it, returning how
+// many events were claimed. Events that fail are rescheduled with backoff.
+func (d *OutboxDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	now := time.Now()
+	events, err := d.outbox.Claim(ctx, now, now.Add(outboxLease), outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Checks of one assignment go out as a single request.
+	var (
+		order     []string
+		deletions []*entity.OutboxEvent
+	)
+	byAssignment := make(map[string][]*entity.OutboxEvent)
+	for _, event := range events {
+		switch event.Type {
+		case entity.OutboxEventSubmissionCreated:
+		case entity.OutboxEventSubmissionDeleted:
+			deletions = append(deletions, event)
+			continue
+		default:
+			d.retry(ctx, event, fmt.Errorf("unknown event type %q", event.Type))
+			continue
+		}
+		if _, ok := byAssignment[event.AssignmentID]; !ok {
+			order = append(order, event.AssignmentID)
+		}
+		byAssignment[event.AssignmentID] = append(byAssignment[event.AssignmentID], event)
+	}
+
+	for _, assignmentID := range order {
+		batch := byAssignment[assignmentID]
+		submissionIDs := make([]string, 0, len(batch))
+		for _, event := range batch {
+			submissionIDs = append(submissionIDs, event.SubmissionID.String())
+		}
+
+		if err := d.checks.StartChecks(ctx, assignmentID, submissionIDs); err != nil {
+			log.Printf("outbox: assignment_id=%s failed to start %d checks: %v", assignmentID, len(batch), err)
+			for _, event := range batch {
+				d.retry(ctx, event, err)
+			}
+			continue
+		}
+		for _, event := range batch {
+			// A failed delete only means the event is delivered again once
+			// its lease runs out, which plagiarism ignores.
+			if err := d.outbox.Delete(ctx, event.ID); err != nil {
+				log.Printf("outbox: event_id=%d delivered but not deleted: %v", event.ID, err)
+			}
+		}
+		log.Printf("outbox: assignment_id=%s started %d checks", assignmentID, len(batch))
+	}
+
+	// Deletions go after the checks, so a submission deleted right after its
+	// upload does not get a report that outlives it.
+	for _, event := range deletions {
+		if err := d.deletions.SubmissionDeleted(ctx, event.AssignmentID, event.SubmissionID.String()); err != nil {
+			log.Printf("outbox: submission_id=%s failed to drop reports: %v", event.SubmissionID, err)
+			d.retry(ctx, event, err)
+			continue
+		}
+		if err := d.outbox.Delete(ctx, event.ID); err != nil {
+			log.Printf("outbox: event_id=%d delivered but not deleted: %v", event.ID, err)
+		}
+	}
+
+	return len(events), nil
+}
+
+func (d *OutboxDispatcher) retry(ctx context.Context, event *entity.OutboxEvent, cause error) {
+	backoff := d.maxBackoff
+	if shift := max(event.Attempts-1, 0); shift < 32 {
+		backoff = min(outboxMinBackoff<<shift, d.maxBackoff)
+	}
+	if err := d.outbox.Retry(ctx, event.ID, time.Now().Add(backoff), cause.Error()); err != nil {
+		log.Printf("outbox: event_id=%d failed to reschedule, retrying after the lease: %v", event.ID, err)
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+
+	"filestorage/internal/application/dto"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// DeletionNotifier tells plagiarism that all of an author's submissions are
+// gone, so it drops their reports and scrubs the author from the others.
+type DeletionNotifier interface {
+	AuthorPurged(ctx context.Context, authorID string, submissions []*entity.Submission) error
+}
+
+type PurgeAuthorUseCase struct {
+	submissionRepo repository.SubmissionRepository
+	s3Repo         repository.S3Repository
+	notifier       DeletionNotifier
+}
+
+func NewPurgeAuthorUseCase(
+	submissionRepo repository.SubmissionRepository,
+	s3Repo repository.S3Repository,
+	notifier DeletionNotifier,
+) *PurgeAuthorUseCase {
+	return &PurgeAuthorUseCase{
+		submissionRepo: submissionRepo,
+		s3Repo:         s3Repo,
+		notifier:       notifier,
+	}
+}
+
+// Purge erases everything stored for the author. Rows are hidden first and
+// dropped only after objects and reports are gone, so a failed run leaves
+// enough behind for a repeated purge to finish the job. Only the submissions
+// listed at the start are dropped: one uploaded while the purge runs keeps
+// its row and file until the next purge.
+func (uc *PurgeAuthorUseCase) Purge(ctx context.Context, req dto.PurgeAuthorRequest) (*dto.PurgeAuthorResponse, error) {
+	if req.AuthorID == "" {
+		return nil, newValidationError("author_id is required")
+	}
+
+	submissions, err := uc.submissionRepo.ListAllByAuthorID(ctx, req.AuthorID)
+	if err != nil {
+		return nil, wrapDatabaseError(err, "failed to list author submissions")
+	}
+
+	submissionIDs := make([]uuid.UUID, 0, len(submissions))
+	for _, sub := range submissions {
+		if sub.DeletedAt == nil {
+			if _, err := uc.submissionRepo.SoftDelete(ctx, sub.SubmissionID, ""); err != nil {
+				return nil, wrapDatabaseError(err, "failed to hide author submission")
+			}
+		}
+		submissionIDs = append(submissionIDs, sub.SubmissionID)
+	}
+
+	for _, sub := range submissions {
+		if err := uc.s3Repo.DeleteFile(ctx, sub.SubmissionID.String()); err != nil {
+			return nil, wrapStorageError(err, "failed to delete submission file")
+		}
+	}
+
+	reportsScrubbed := false
+	if uc.notifier != nil {
+		if err := uc.notifier.AuthorPurged(ctx, req.AuthorID, submissions); err != nil {
+			return nil, wrapStorageError(err, "failed to purge plagiarism reports")
+		}
+		reportsScrubbed = true
+	} else {
+		log.Printf("purge: plagiarism notifications disabled, reports were not scrubbed")
+	}
+
+	audit, err := uc.submissionRepo.PurgeByAuthorID(ctx, req.AuthorID, &entity.PurgeAudit{
+		AuthorHash:      hashAuthorID(req.AuthorID),
+		SubmissionIDs:   submissionIDs,
+		ObjectsDeleted:  len(submissions),
+		ReportsScrubbed: reportsScrubbed,
+		RequestedBy:     req.RequestedBy,
+	})
+	if err != nil {
+		return nil, wrapDatabaseError(err, "failed to purge author submissions")
+	}
+
+	purged := make([]string, 0, len(audit.SubmissionIDs))
+	for _, id := range audit.SubmissionIDs {
+		purged = append(purged, id.String())
+	}
+
+	return &dto.PurgeAuthorResponse{
+		AuditID:         audit.AuditID.String(),
+		AuthorHash:      audit.AuthorHash,
+		SubmissionIDs:   purged,
+		ObjectsDeleted:  audit.ObjectsDeleted,
+		ReportsScrubbed: audit.ReportsScrubbed,
+		RequestedBy:     audit.RequestedBy,
+		CreatedAt:       audit.CreatedAt,
+	}, nil
+}
+
+func hashAuthorID(authorID string) string {
+	sum := sha256.Sum256([]byte(authorID))
+	return hex.EncodeToString(sum[:])
+}
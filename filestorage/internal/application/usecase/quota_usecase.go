@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"filestorage/internal/application/dto"
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+)
+
+// Quota limits what a single author may store. Zero means unlimited.
+type Quota struct {
+	MaxSubmissionsPerAssignment int64
+	MaxBytesPerAuthor           int64
+}
+
+// check rejects a new submission that would not fit into the quota at all.
+// Whether the file itself fits is only known while it is being read.
+func (q Quota) check(usage *entity.Usage) error {
+	if q.MaxSubmissionsPerAssignment > 0 && usage.AssignmentSubmissions >= q.MaxSubmissionsPerAssignment {
+		return apperr.New(apperr.CodeQuotaExceeded, fmt.Sprintf("submission limit of %d per assignment reached", q.MaxSubmissionsPerAssignment))
+	}
+	if q.MaxBytesPerAuthor > 0 && usage.BytesUsed >= q.MaxBytesPerAuthor {
+		return q.storageExceeded()
+	}
+	return nil
+}
+
+func (q Quota) storageExceeded() error {
+	return apperr.New(apperr.CodeStorageQuotaExceeded, fmt.Sprintf("storage limit of %d bytes per author reached", q.MaxBytesPerAuthor))
+}
+
+// remainingBytes is negative when storage is unlimited.
+func (q Quota) remainingBytes(usage *entity.Usage) int64 {
+	if q.MaxBytesPerAuthor <= 0 {
+		return -1
+	}
+	return max(q.MaxBytesPerAuthor-usage.BytesUsed, 0)
+}
+
+type QuotaUseCase struct {
+	submissionRepo repository.SubmissionRepository
+	quota          Quota
+}
+
+func NewQuotaUseCase(submissionRepo repository.SubmissionRepository, quota Quota) *QuotaUseCase {
+	return &QuotaUseCase{
+		submissionRepo: submissionRepo,
+		quota:          quota,
+	}
+}
+
+func (uc *QuotaUseCase) Usage(ctx context.Context, authorID, assignmentID string) (*dto.QuotaUsageResponse, error) {
+	if authorID == "" {
+		return nil, newValidationError("author_id is required")
+	}
+	if assignmentID == "" {
+		return nil, newValidationError("assignment_id is required")
+	}
+
+	usage, err := uc.submissionRepo.GetUsage(ctx, authorID, assignmentID)
+	if err != nil {
+		return nil, wrapDatabaseError(err, "failed to get author usage")
+	}
+
+	resp := &dto.QuotaUsageResponse{
+		AuthorID:         authorID,
+		AssignmentID:     assignmentID,
+		SubmissionsUsed:  usage.AssignmentSubmissions,
+		SubmissionsLimit: uc.quota.MaxSubmissionsPerAssignment,
+		BytesUsed:        usage.BytesUsed,
+		BytesLimit:       uc.quota.MaxBytesPerAuthor,
+	}
+	if uc.quota.MaxSubmissionsPerAssignment > 0 {
+		remaining := max(uc.quota.MaxSubmissionsPerAssignment-usage.AssignmentSubmissions, 0)
+		resp.SubmissionsRemaining = &remaining
+	}
+	if remaining := uc.quota.remainingBytes(usage); remaining >= 0 {
+		resp.BytesRemaining = &remaining
+	}
+	return resp, nil
+}
+
+var errStorageQuotaExceeded = errors.New("storage quota exceeded")
+
+// quotaReader fails the upload as soon as it outgrows the author's
+// remaining storage. The storage backend wraps read errors in its own, so
+// exceeded is what tells the two apart.
+type quotaReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func newQuotaReader(r io.Reader, remaining int64) io.Reader {
+	if remaining < 0 {
+		return r
+	}
+	return &quotaReader{r: r, remaining: remaining}
+}
+
+func (q *quotaReader) Read(p []byte) (int, error) {
+	n, err := q.r.Read(p)
+	q.remaining -= int64(n)
+	if q.remaining < 0 {
+		q.exceeded = true
+		return 0, errStorageQuotaExceeded
+	}
+	return n, err
+}
+
+func quotaExceeded(r io.Reader) bool {
+	q, ok := r.(*quotaReader)
+	return ok && q.exceeded
+}
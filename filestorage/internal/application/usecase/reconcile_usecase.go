@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"filestorage/internal/application/dto"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const reconcilePageSize = 500
+
+type ReconcileUseCase struct {
+	submissionRepo repository.SubmissionRepository
+	s3Repo         repository.S3Repository
+	grace          time.Duration
+
+	mu sync.Mutex
+}
+
+// NewReconcileUseCase builds the job that compares submission rows with
+// stored objects. Anything younger than grace is left alone: an upload
+// writes the object before committing the row, so a fresh object without a
+// row is normal for a moment.
+func NewReconcileUseCase(
+	submissionRepo repository.SubmissionRepository,
+	s3Repo repository.S3Repository,
+	grace time.Duration,
+) *ReconcileUseCase {
+	return &ReconcileUseCase{
+		submissionRepo: submissionRepo,
+		s3Repo:         s3Repo,
+		grace:          grace,
+	}
+}
+
+// Reconcile reports objects without a live row and rows without an object.
+// With repair set, orphan objects are deleted and rows are flagged
+// file_missing (or flipped back to active once the object reappears).
+func (uc *ReconcileUseCase) Reconcile(ctx context.Context, repair bool) (*dto.ReconcileReport, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	report := &dto.ReconcileReport{
+		StartedAt: time.Now().UTC(),
+		Repair:    repair,
+		Issues:    make([]dto.ReconcileIssue, 0),
+	}
+	cutoff := report.StartedAt.Add(-uc.grace)
+
+	// Objects are listed before rows so that an upload finishing in between
+	// shows up as a fresh row, which the grace period covers.
+	objectList, err := uc.s3Repo.ListObjects(ctx)
+	if err != nil {
+		return nil, wrapStorageError(err, "failed to list objects")
+	}
+	report.ObjectsScanned = len(objectList)
+
+	objects := make(map[string]repository.ObjectInfo, len(objectList))
+	for _, obj := range objectList {
+		objects[obj.Key] = obj
+	}
+
+	after := uuid.Nil
+	for {
+		rows, err := uc.submissionRepo.ListAfterID(ctx, after, reconcilePageSize)
+		if err != nil {
+			return nil, wrapDatabaseError(err, "failed to list submissions")
+		}
+
+		for _, sub := range rows {
+			report.RowsScanned++
+			key := sub.SubmissionID.String()
+			_, hasObject := objects[key]
+
+			switch {
+			case sub.DeletedAt != nil:
+				// Objects of deleted rows are reported with the orphans below.
+				continue
+			case !hasObject && sub.Status == entity.SubmissionStatusActive && sub.CreatedAt.Before(cutoff):
+				issue := dto.ReconcileIssue{Kind: dto.ReconcileIssueMissingFile, Key: key}
+				if repair {
+					issue.Repaired = uc.setStatus(ctx, sub, entity.SubmissionStatusFileMissing)
+				}
+				report.Issues = append(report.Issues, issue)
+			case hasObject && sub.Status == entity.SubmissionStatusFileMissing:
+				issue := dto.ReconcileIssue{Kind: dto.ReconcileIssueFileRestored, Key: key}
+				if repair {
+					issue.Repaired = uc.setStatus(ctx, sub, entity.SubmissionStatusActive)
+				}
+				report.Issues = append(report.Issues, issue)
+			}
+			delete(objects, key)
+		}
+
+		if len(rows) < reconcilePageSize {
+			break
+		}
+		after = rows[len(rows)-1].SubmissionID
+	}
+
+	orphans := make([]string, 0, len(objects))
+	for key, obj := range objects {
+		if obj.LastModified.Before(cutoff) {
+			orphans = append(orphans, key)
+		}
+	}
+	sort.Strings(orphans)
+
+	for _, key := range orphans {
+		issue := dto.ReconcileIssue{Kind: dto.ReconcileIssueOrphanObject, Key: key}
+		if repair {
+			if err := uc.s3Repo.DeleteFile(ctx, key); err != nil {
+				log.Printf("reconcile: delete orphan object key=%s failed: %v", key, err)
+			} else {
+				issue.Repaired = true
+			}
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+func (uc *ReconcileUseCase) setStatus(ctx context.Context, sub *entity.Submission, status entity.SubmissionStatus) bool {
+	if err := uc.submissionRepo.SetStatus(ctx, sub.SubmissionID, status); err != nil {
+		log.Printf("reconcile: set status=%s submission_id=%s failed: %v", status, sub.SubmissionID, err)
+		return false
+	}
+	return true
+}
+
+// RunPeriodically reconciles every interval until ctx is cancelled.
+func (uc *ReconcileUseCase) RunPeriodically(ctx context.Context, interval time.Duration, repair bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := uc.Reconcile(ctx, repair)
+			if err != nil {
+				log.Printf("reconcile: failed: %v", err)
+				continue
+			}
+			log.Printf("reconcile: objects=%d rows=%d issues=%d repair=%t",
+				report.ObjectsScanned, report.RowsScanned, len(report.Issues), repair)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"filestorage/internal/domain/entity"
+)
+
+// MalwareScanner inspects uploaded files as they are stored.
+type MalwareScanner interface {
+	Scan(ctx context.Context, r io.Reader) (*entity.ScanResult, error)
+}
+
+// scanningReader feeds everything the upload reads to the scanner as it
+// goes, so the file is neither buffered nor read twice.
+type scanningReader struct {
+	r      io.Reader
+	pw     *io.PipeWriter
+	done   chan struct{}
+	result *entity.ScanResult
+	err    error
+}
+
+func startScan(ctx context.Context, scanner MalwareScanner, r io.Reader) *scanningReader {
+	pr, pw := io.Pipe()
+	s := &scanningReader{
+		r:    io.TeeReader(r, pw),
+		pw:   pw,
+		done: make(chan struct{}),
+	}
+	go func() {
+		result, err := scanner.Scan(ctx, pr)
+		s.result, s.err = result, err
+		close(s.done)
+		if err != nil {
+			// Fails the upload too; it could not be accepted anyway.
+			pr.CloseWithError(err)
+			return
+		}
+		// A scanner may stop reading once it has a verdict.
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+	return s
+}
+
+func (s *scanningReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err == io.EOF {
+		s.pw.Close()
+	}
+	return n, err
+}
+
+// wait returns the verdict once the upload has read the whole file.
+func (s *scanningReader) wait() (*entity.ScanResult, error) {
+	s.pw.Close()
+	<-s.done
+	return s.result, s.err
+}
+
+// abort stops the scan after a failed upload. It returns the scanner's
+// error when the scanner failed first and so caused the upload to fail.
+func (s *scanningReader) abort(cause error) error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+	}
+	s.pw.CloseWithError(cause)
+	<-s.done
+	return nil
+}
@@ -2,30 +2,91 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"io"
 	"log"
+	"time"
 
 	"filestorage/internal/application/dto"
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
 	"filestorage/internal/domain/repository"
 )
 
 type SubmitUseCase struct {
 	submissionRepo repository.SubmissionRepository
 	s3Repo         repository.S3Repository
+	outbox         repository.OutboxRepository
+	events         *OutboxDispatcher
+	scanner        MalwareScanner
+	quota          Quota
+	policy         UploadPolicy
 }
 
+// NewSubmitUseCase builds the upload. Files are checked against policy
+// before anything is stored. Each stored submission gets a
+// submission_created event in the outbox, committed together with it;
+// events, if not nil, is woken up to deliver it.
 func NewSubmitUseCase(
 	submissionRepo repository.SubmissionRepository,
 	s3Repo repository.S3Repository,
+	outbox repository.OutboxRepository,
+	events *OutboxDispatcher,
+	scanner MalwareScanner,
+	quota Quota,
+	policy UploadPolicy,
 ) *SubmitUseCase {
 	return &SubmitUseCase{
 		submissionRepo: submissionRepo,
 		s3Repo:         s3Repo,
+		outbox:         outbox,
+		events:         events,
+		scanner:        scanner,
+		quota:          quota,
+		policy:         policy,
 	}
 }
 
 func (uc *SubmitUseCase) Submit(ctx context.Context, req dto.SubmitRequest) (*dto.SubmitResponse, error) {
-	submission, tx, err := uc.submissionRepo.CreateWithTx(ctx, req.AssignmentID, req.Login)
+	// Concurrent uploads by the same author may each pass this check, so a
+	// quota can be overshot by the uploads in flight, but not by more.
+	usage, err := uc.submissionRepo.GetUsage(ctx, req.Login, req.AssignmentID)
+	if err != nil {
+		return nil, wrapDatabaseError(err, "failed to get author usage")
+	}
+	if err := uc.quota.check(usage); err != nil {
+		return nil, err
+	}
+
+	return uc.store(ctx, req, uc.quota.remainingBytes(usage), false)
+}
+
+// store creates the submission and uploads its file, refusing to write more
+// than remainingBytes (negative means unlimited). With holdCheck set, the
+// check event stays in the outbox until it is released.
+func (uc *SubmitUseCase) store(ctx context.Context, req dto.SubmitRequest, remainingBytes int64, holdCheck bool) (*dto.SubmitResponse, error) {
+	upload, err := inspectUpload(req.File, req.Filename, uc.policy.For(req.AssignmentID))
+	if err != nil {
+		if apperr.IsCode(err, apperr.CodeValidation) {
+			log.Printf("submit: assignment_id=%s login=%s file %q rejected: %v", req.AssignmentID, req.Login, req.Filename, err)
+			return nil, err
+		}
+		return nil, apperr.Wrap(err, apperr.CodeValidation, "failed to read file")
+	}
+	defer upload.Close()
+
+	var (
+		submission *entity.Submission
+		tx         repository.Transaction
+	)
+	if req.SubmittedAt.IsZero() {
+		submission, tx, err = uc.submissionRepo.CreateWithTx(ctx, req.AssignmentID, req.Login)
+	} else {
+		submission, tx, err = uc.submissionRepo.CreateAtWithTx(ctx, req.AssignmentID, req.Login, req.SubmittedAt)
+	}
 	if err != nil {
 		return nil, wrapDatabaseError(err, "failed to create submission")
 	}
@@ -36,12 +97,77 @@ func (uc *SubmitUseCase) Submit(ctx context.Context, req dto.SubmitRequest) (*dt
 	}()
 
 	s3Key := submission.SubmissionID.String()
+	body := newDigestReader(upload)
+	limited := newQuotaReader(body, remainingBytes)
+	scan := startScan(ctx, uc.scanner, limited)
 
-	if err := uc.s3Repo.UploadFile(ctx, s3Key, req.Data, req.ContentType); err != nil {
+	if err := uc.s3Repo.UploadFile(ctx, s3Key, scan, upload.contentType); err != nil {
+		if scanErr := scan.abort(err); scanErr != nil {
+			log.Printf("submit: submission_id=%s malware scan failed: %v", submission.SubmissionID.String(), scanErr)
+			return nil, apperr.Wrap(scanErr, apperr.CodeUnavailable, "malware scanner unavailable")
+		}
+		if quotaExceeded(limited) {
+			return nil, uc.quota.storageExceeded()
+		}
+		if rejectErr := upload.Err(); rejectErr != nil {
+			log.Printf("submit: assignment_id=%s login=%s file %q rejected: %v", req.AssignmentID, req.Login, req.Filename, rejectErr)
+			return nil, rejectErr
+		}
 		log.Printf("submit: submission_id=%s failed to upload to s3 key=%s: %v", submission.SubmissionID.String(), s3Key, err)
 		return nil, wrapStorageError(err, "failed to upload file to storage")
 	}
 
+	verdict, err := scan.wait()
+	if err != nil {
+		log.Printf("submit: submission_id=%s malware scan failed, deleting s3 key=%s: %v", submission.SubmissionID.String(), s3Key, err)
+		if delErr := uc.s3Repo.DeleteFile(ctx, s3Key); delErr != nil {
+			log.Printf("submit: submission_id=%s cleanup of s3 key=%s failed: %v", submission.SubmissionID.String(), s3Key, delErr)
+		}
+		return nil, apperr.Wrap(err, apperr.CodeUnavailable, "malware scanner unavailable")
+	}
+
+	status := entity.SubmissionStatusActive
+	if verdict.Infected {
+		status = entity.SubmissionStatusQuarantined
+		log.Printf("submit: submission_id=%s quarantined, scanner found %q", submission.SubmissionID.String(), verdict.Signature)
+	}
+
+	checksum := body.Checksum()
+	fileInfo := entity.FileInfo{
+		SizeBytes:        body.Size(),
+		Checksum:         checksum,
+		ContentType:      upload.contentType,
+		Status:           status,
+		OriginalFilename: req.Filename,
+	}
+	if err := uc.submissionRepo.SetFileInfoWithTx(ctx, tx, submission.SubmissionID, fileInfo); err != nil {
+		log.Printf("submit: submission_id=%s failed to store file info, deleting s3 key=%s: %v", submission.SubmissionID.String(), s3Key, err)
+		if delErr := uc.s3Repo.DeleteFile(ctx, s3Key); delErr != nil {
+			log.Printf("submit: submission_id=%s cleanup of s3 key=%s failed: %v", submission.SubmissionID.String(), s3Key, delErr)
+		}
+		return nil, wrapDatabaseError(err, "failed to store submission file info")
+	}
+
+	// Quarantined files are never checked.
+	if status == entity.SubmissionStatusActive {
+		event := &entity.OutboxEvent{
+			Type:         entity.OutboxEventSubmissionCreated,
+			AssignmentID: req.AssignmentID,
+			SubmissionID: submission.SubmissionID,
+		}
+		if !holdCheck {
+			now := time.Now()
+			event.AvailableAt = &now
+		}
+		if err := uc.outbox.AddWithTx(ctx, tx, event); err != nil {
+			log.Printf("submit: submission_id=%s failed to store outbox event, deleting s3 key=%s: %v", submission.SubmissionID.String(), s3Key, err)
+			if delErr := uc.s3Repo.DeleteFile(ctx, s3Key); delErr != nil {
+				log.Printf("submit: submission_id=%s cleanup of s3 key=%s failed: %v", submission.SubmissionID.String(), s3Key, delErr)
+			}
+			return nil, wrapDatabaseError(err, "failed to store submission event")
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		log.Printf("submit: submission_id=%s commit failed, deleting s3 key=%s: %v", submission.SubmissionID.String(), s3Key, err)
 		if delErr := uc.s3Repo.DeleteFile(ctx, s3Key); delErr != nil {
@@ -51,8 +177,43 @@ func (uc *SubmitUseCase) Submit(ctx context.Context, req dto.SubmitRequest) (*dt
 		return nil, wrapDatabaseError(err, "failed to commit submission tx")
 	}
 	tx = nil
+	if !holdCheck {
+		uc.events.Notify()
+	}
 
 	return &dto.SubmitResponse{
 		SubmissionID: submission.SubmissionID.String(),
+		SizeBytes:    body.Size(),
+		Checksum:     checksum,
+		ContentType:  upload.contentType,
+		Status:       string(status),
+		Signature:    verdict.Signature,
 	}, nil
 }
+
+type digestReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func newDigestReader(r io.Reader) *digestReader {
+	return &digestReader{r: r, hash: sha256.New()}
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if n > 0 {
+		d.hash.Write(p[:n])
+		d.size += int64(n)
+	}
+	return n, err
+}
+
+func (d *digestReader) Size() int64 {
+	return d.size
+}
+
+func (d *digestReader) Checksum() string {
+	return hex.EncodeToString(d.hash.Sum(nil))
+}
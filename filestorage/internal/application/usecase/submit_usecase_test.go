@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"filestorage/internal/application/dto"
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+	"filestorage/internal/infrastructure/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+type scannerFunc func(ctx context.Context, r io.Reader) (*entity.ScanResult, error)
+
+func (f scannerFunc) Scan(ctx context.Context, r io.Reader) (*entity.ScanResult, error) {
+	return f(ctx, r)
+}
+
+type fakeTx struct {
+	committed bool
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	return nil
+}
+
+// fakeSubmissionRepo implements what Submit needs; anything else panics.
+type fakeSubmissionRepo struct {
+	repository.SubmissionRepository
+	submission *entity.Submission
+	tx         *fakeTx
+	info       *entity.FileInfo
+}
+
+func (r *fakeSubmissionRepo) GetUsage(context.Context, string, string) (*entity.Usage, error) {
+	return &entity.Usage{}, nil
+}
+
+func (r *fakeSubmissionRepo) CreateWithTx(_ context.Context, assignmentID, authorID string) (*entity.Submission, repository.Transaction, error) {
+	r.submission = &entity.Submission{SubmissionID: uuid.New(), AssignmentID: assignmentID, AuthorID: authorID}
+	r.tx = &fakeTx{}
+	return r.submission, r.tx, nil
+}
+
+func (r *fakeSubmissionRepo) SetFileInfoWithTx(_ context.Context, _ repository.Transaction, _ uuid.UUID, info entity.FileInfo) error {
+	r.info = &info
+	return nil
+}
+
+type fakeOutbox struct {
+	repository.OutboxRepository
+	events []*entity.OutboxEvent
+}
+
+func (o *fakeOutbox) AddWithTx(_ context.Context, _ repository.Transaction, event *entity.OutboxEvent) error {
+	o.events = append(o.events, event)
+	return nil
+}
+
+func TestScanningReader(t *testing.T) {
+	file := bytes.Repeat([]byte("def f():\n    return 1\n"), 10000)
+
+	t.Run("scanner sees what the upload reads", func(t *testing.T) {
+		var scanned []byte
+		scan := startScan(context.Background(), scannerFunc(func(_ context.Context, r io.Reader) (*entity.ScanResult, error) {
+			var err error
+			scanned, err = io.ReadAll(r)
+			return &entity.ScanResult{}, err
+		}), bytes.NewReader(file))
+
+		uploaded, err := io.ReadAll(scan)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if _, err := scan.wait(); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+		if !bytes.Equal(uploaded, file) || !bytes.Equal(scanned, file) {
+			t.Errorf("uploaded %d and scanned %d bytes, want %d", len(uploaded), len(scanned), len(file))
+		}
+	})
+
+	t.Run("scanner stopping early does not block the upload", func(t *testing.T) {
+		scan := startScan(context.Background(), scannerFunc(func(_ context.Context, r io.Reader) (*entity.ScanResult, error) {
+			_, err := io.ReadFull(r, make([]byte, 16))
+			return &entity.ScanResult{Infected: true, Signature: "Test"}, err
+		}), bytes.NewReader(file))
+
+		uploaded, err := io.ReadAll(scan)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		verdict, err := scan.wait()
+		if err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+		if len(uploaded) != len(file) || !verdict.Infected {
+			t.Errorf("uploaded %d bytes with verdict %+v", len(uploaded), verdict)
+		}
+	})
+
+	t.Run("scanner failure fails the upload", func(t *testing.T) {
+		scanErr := errors.New("clamd is down")
+		scan := startScan(context.Background(), scannerFunc(func(context.Context, io.Reader) (*entity.ScanResult, error) {
+			return nil, scanErr
+		}), bytes.NewReader(file))
+
+		_, err := io.ReadAll(scan)
+		if !errors.Is(err, scanErr) {
+			t.Fatalf("read error = %v, want %v", err, scanErr)
+		}
+		if got := scan.abort(err); !errors.Is(got, scanErr) {
+			t.Errorf("abort() = %v, want %v", got, scanErr)
+		}
+	})
+}
+
+func TestSubmitScanVerdict(t *testing.T) {
+	tests := []struct {
+		name       string
+		scanner    scannerFunc
+		wantStatus entity.SubmissionStatus
+		wantEvents int
+		wantCode   apperr.Code
+	}{
+		{
+			name: "clean file is stored and checked",
+			scanner: func(_ context.Context, r io.Reader) (*entity.ScanResult, error) {
+				_, err := io.Copy(io.Discard, r)
+				return &entity.ScanResult{}, err
+			},
+			wantStatus: entity.SubmissionStatusActive,
+			wantEvents: 1,
+		},
+		{
+			name: "infected file is quarantined and not checked",
+			scanner: func(_ context.Context, r io.Reader) (*entity.ScanResult, error) {
+				_, err := io.Copy(io.Discard, r)
+				return &entity.ScanResult{Infected: true, Signature: "Eicar-Test-Signature"}, err
+			},
+			wantStatus: entity.SubmissionStatusQuarantined,
+		},
+		{
+			name: "scanner failure rejects the file",
+			scanner: func(_ context.Context, r io.Reader) (*entity.ScanResult, error) {
+				_, _ = io.Copy(io.Discard, r)
+				return nil, errors.New("clamd is down")
+			},
+			wantCode: apperr.CodeUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSubmissionRepo{}
+			outbox := &fakeOutbox{}
+			store := memory.NewMemoryRepository()
+			uc := NewSubmitUseCase(repo, store, outbox, nil, tt.scanner, Quota{}, UploadPolicy{})
+
+			resp, err := uc.Submit(context.Background(), dto.SubmitRequest{
+				AssignmentID: "hw1",
+				Login:        "alice",
+				File:         strings.NewReader("print('hello')\n"),
+				Filename:     "main.py",
+			})
+			objects, listErr := store.ListObjects(context.Background())
+			if listErr != nil {
+				t.Fatalf("list objects: %v", listErr)
+			}
+
+			if tt.wantCode != "" {
+				if !apperr.IsCode(err, tt.wantCode) {
+					t.Fatalf("Submit() error = %v, want code %s", err, tt.wantCode)
+				}
+				if repo.tx.committed || len(objects) != 0 {
+					t.Errorf("rejected file left committed=%t objects=%d", repo.tx.committed, len(objects))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+
+			if !repo.tx.committed {
+				t.Error("submission was not committed")
+			}
+			if len(objects) != 1 {
+				t.Errorf("stored %d objects, want 1", len(objects))
+			}
+			if repo.info == nil || repo.info.Status != tt.wantStatus {
+				t.Errorf("file info = %+v, want status %s", repo.info, tt.wantStatus)
+			}
+			if resp.Status != string(tt.wantStatus) {
+				t.Errorf("response status = %s, want %s", resp.Status, tt.wantStatus)
+			}
+			if len(outbox.events) != tt.wantEvents {
+				t.Errorf("outbox events = %d, want %d", len(outbox.events), tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestSubmitUploadPolicy(t *testing.T) {
+	policy := UploadPolicy{
+		Assignments: map[string]AllowList{"hw1": {Extensions: []string{".py"}}},
+	}
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantErr  bool
+	}{
+		{name: "allowed", filename: "main.py", content: "print('hello')\n"},
+		{name: "extension not allowed", filename: "main.txt", content: "print('hello')\n", wantErr: true},
+		{name: "executable", filename: "main.py", content: "\x7fELF\x02\x01\x01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSubmissionRepo{}
+			store := memory.NewMemoryRepository()
+			uc := NewSubmitUseCase(repo, store, &fakeOutbox{}, nil, scannerFunc(func(_ context.Context, r io.Reader) (*entity.ScanResult, error) {
+				_, err := io.Copy(io.Discard, r)
+				return &entity.ScanResult{}, err
+			}), Quota{}, policy)
+
+			resp, err := uc.Submit(context.Background(), dto.SubmitRequest{
+				AssignmentID: "hw1",
+				Login:        "alice",
+				File:         strings.NewReader(tt.content),
+				Filename:     tt.filename,
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Submit() error = %v", err)
+				}
+				if resp.ContentType != "text/plain" {
+					t.Errorf("content type = %s, want text/plain", resp.ContentType)
+				}
+				return
+			}
+			if !apperr.IsCode(err, apperr.CodeValidation) {
+				t.Fatalf("Submit() error = %v, want a validation error", err)
+			}
+			if repo.submission != nil {
+				t.Error("rejected file created a submission")
+			}
+		})
+	}
+}
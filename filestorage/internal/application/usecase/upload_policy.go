@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// sniffLen is how much of the file decides its type, as in
+// http.DetectContentType.
+const sniffLen = 512
+
+// AllowList restricts what may be uploaded. Empty lists allow anything;
+// when both are set a file must match both.
+type AllowList struct {
+	// Extensions like ".py", lower case.
+	Extensions []string
+	// MIMETypes like "text/plain" or "image/*", matched against the type
+	// sniffed from the content.
+	MIMETypes []string
+}
+
+// UploadPolicy decides which files an assignment accepts. Executables are
+// rejected whatever the allowlist says.
+type UploadPolicy struct {
+	Default     AllowList
+	Assignments map[string]AllowList
+}
+
+// For returns the allowlist of an assignment, falling back to the default.
+func (p UploadPolicy) For(assignmentID string) AllowList {
+	if list, ok := p.Assignments[assignmentID]; ok {
+		return list
+	}
+	return p.Default
+}
+
+// rejectUpload is a validation error telling the client why the file was
+// refused.
+func rejectUpload(format string, args ...any) error {
+	return newValidationError(fmt.Sprintf(format, args...))
+}
+
+var executableMagics = []struct {
+	magic []byte
+	name  string
+}{
+	{[]byte("\x7fELF"), "ELF executable"},
+	{[]byte{0xfe, 0xed, 0xfa, 0xce}, "Mach-O executable"},
+	{[]byte{0xfe, 0xed, 0xfa, 0xcf}, "Mach-O executable"},
+	{[]byte{0xce, 0xfa, 0xed, 0xfe}, "Mach-O executable"},
+	{[]byte{0xcf, 0xfa, 0xed, 0xfe}, "Mach-O executable"},
+	{[]byte{0xca, 0xfe, 0xba, 0xbe}, "Mach-O universal binary or Java class file"},
+}
+
+func detectExecutable(head []byte) (string, bool) {
+	if isPortableExecutable(head) {
+		return "Windows executable", true
+	}
+	for _, m := range executableMagics {
+		if bytes.HasPrefix(head, m.magic) {
+			return m.name, true
+		}
+	}
+	return "", false
+}
+
+// isPortableExecutable requires the PE signature the DOS header points to,
+// since "MZ" alone also starts ordinary text.
+func isPortableExecutable(head []byte) bool {
+	if len(head) < 0x40 || !bytes.HasPrefix(head, []byte("MZ")) {
+		return false
+	}
+	offset := int(binary.LittleEndian.Uint32(head[0x3c:]))
+	return offset+4 <= len(head) && bytes.Equal(head[offset:offset+4], []byte("PE\x00\x00"))
+}
+
+// detectContentType returns the bare MIME type of head, without parameters.
+func detectContentType(head []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// inspectedUpload is the file body after its first bytes were checked. Zip
+// archives are additionally spooled to a temporary file and validated once
+// fully read; a bad archive fails the final Read, which aborts the upload
+// before anything is committed.
+type inspectedUpload struct {
+	io.Reader
+	contentType string
+	archive     *zipCheck
+}
+
+// Err returns why the archive was rejected, if it was.
+func (u *inspectedUpload) Err() error {
+	if u.archive == nil {
+		return nil
+	}
+	return u.archive.err
+}
+
+func (u *inspectedUpload) Close() {
+	if u.archive != nil {
+		u.archive.close()
+	}
+}
+
+// inspectUpload sniffs the type of the file and checks it against allow.
+// The type the client claims is never trusted.
+func inspectUpload(r io.Reader, filename string, allow AllowList) (*inspectedUpload, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if name, ok := detectExecutable(head); ok {
+		return nil, rejectUpload("executable files are not allowed (detected %s)", name)
+	}
+	contentType := detectContentType(head)
+	if err := checkAllowList(filename, contentType, allow); err != nil {
+		return nil, err
+	}
+
+	upload := &inspectedUpload{Reader: br, contentType: contentType}
+	switch contentType {
+	case "application/zip":
+		archive, err := newZipCheck(br)
+		if err != nil {
+			return nil, err
+		}
+		upload.Reader, upload.archive = archive, archive
+	case "application/x-gzip":
+		if _, err := gzip.NewReader(bytes.NewReader(head)); err != nil && err != io.ErrUnexpectedEOF {
+			return nil, rejectUpload("malformed gzip archive: %v", err)
+		}
+	}
+	return upload, nil
+}
+
+func checkAllowList(filename, contentType string, allow AllowList) error {
+	if len(allow.Extensions) > 0 {
+		ext := strings.ToLower(filepath.Ext(filename))
+		if !slices.Contains(allow.Extensions, ext) {
+			return rejectUpload("file extension %q is not allowed for this assignment (allowed: %s)", ext, strings.Join(allow.Extensions, ", "))
+		}
+	}
+	if len(allow.MIMETypes) > 0 && !mimeAllowed(contentType, allow.MIMETypes) {
+		return rejectUpload("file content is %s, which is not allowed for this assignment (allowed: %s)", contentType, strings.Join(allow.MIMETypes, ", "))
+	}
+	return nil
+}
+
+func mimeAllowed(contentType string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*/*" || pattern == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type zipCheck struct {
+	r    io.Reader
+	tmp  *os.File
+	size int64
+	err  error
+}
+
+func newZipCheck(r io.Reader) (*zipCheck, error) {
+	tmp, err := os.CreateTemp("", "filestorage-upload-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	return &zipCheck{r: r, tmp: tmp}, nil
+}
+
+func (z *zipCheck) Read(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	n, err := z.r.Read(p)
+	if n > 0 {
+		if _, werr := z.tmp.Write(p[:n]); werr != nil {
+			return n, werr
+		}
+		z.size += int64(n)
+	}
+	if err == io.EOF {
+		if verr := z.validate(); verr != nil {
+			z.err = verr
+			return n, verr
+		}
+	}
+	return n, err
+}
+
+// validate checks the central directory, that entry names stay inside the
+// archive and that no entry is an executable. Entries are not decompressed
+// beyond their first bytes.
+func (z *zipCheck) validate() error {
+	zr, err := zip.NewReader(z.tmp, z.size)
+	if err != nil {
+		return rejectUpload("malformed zip archive: %v", err)
+	}
+	for _, f := range zr.File {
+		if !filepath.IsLocal(f.Name) || strings.Contains(f.Name, `\`) {
+			return rejectUpload("zip archive entry %q has an unsafe path", f.Name)
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return rejectUpload("malformed zip archive: entry %q: %v", f.Name, err)
+		}
+		head := make([]byte, sniffLen)
+		n, err := io.ReadFull(rc, head)
+		rc.Close()
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return rejectUpload("malformed zip archive: entry %q: %v", f.Name, err)
+		}
+		if name, ok := detectExecutable(head[:n]); ok {
+			return rejectUpload("zip archive entry %q is an executable (%s)", f.Name, name)
+		}
+	}
+	return nil
+}
+
+func (z *zipCheck) close() {
+	z.tmp.Close()
+	os.Remove(z.tmp.Name())
+}
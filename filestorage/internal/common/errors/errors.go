@@ -9,9 +9,22 @@ type Code string
 const (
 	CodeValidation Code = "validation_error"
 	CodeNotFound   Code = "not_found"
+	CodeForbidden  Code = "forbidden"
 	CodeDatabase   Code = "database_error"
 	CodeStorage    Code = "storage_error"
 	CodeInternal   Code = "internal_error"
+	// CodeUnsupported means the configured backend lacks the feature.
+	CodeUnsupported Code = "unsupported"
+	// CodeUnavailable means a required dependency could not be reached.
+	CodeUnavailable Code = "unavailable"
+	// CodeQuotaExceeded means the author has used up their submissions.
+	CodeQuotaExceeded Code = "quota_exceeded"
+	// CodeConflict means the request clashes with one made earlier, e.g. an
+	// Idempotency-Key still in use.
+	CodeConflict Code = "conflict"
+	// CodeStorageQuotaExceeded means the upload does not fit in the
+	// author's remaining storage.
+	CodeStorageQuotaExceeded Code = "storage_quota_exceeded"
 )
 
 type Error struct {
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// IdempotencyKey remembers the response to a request sent with an
+// Idempotency-Key header so that retries of it get the same response.
+type IdempotencyKey struct {
+	// Scope is who the key belongs to; keys of different callers never
+	// collide.
+	Scope string
+	Key   string
+	// Fingerprint identifies the request the key was first used for.
+	Fingerprint string
+	// StatusCode is 0 while the first request is still being processed.
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+func (k *IdempotencyKey) InProgress() bool {
+	return k.StatusCode == 0
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEventType string
+
+const (
+	// OutboxEventSubmissionCreated asks plagiarism to check a new submission.
+	OutboxEventSubmissionCreated OutboxEventType = "submission_created"
+	// OutboxEventSubmissionDeleted asks plagiarism to drop the reports of a
+	// deleted submission.
+	OutboxEventSubmissionDeleted OutboxEventType = "submission_deleted"
+)
+
+// OutboxEvent is a message stored in the same transaction as the change it
+// announces and delivered afterwards, until delivery succeeds.
+type OutboxEvent struct {
+	ID           int64
+	Type         OutboxEventType
+	AssignmentID string
+	SubmissionID uuid.UUID
+	CreatedAt    time.Time
+	// AvailableAt is when the event may next be delivered; nil holds it back
+	// until it is released.
+	AvailableAt *time.Time
+	Attempts    int
+	LastError   string
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PurgeAudit records an erasure of all data of one author. The author is
+// kept only as a hash so the record itself does not retain the identifier.
+type PurgeAudit struct {
+	AuditID         uuid.UUID
+	AuthorHash      string
+	SubmissionIDs   []uuid.UUID
+	ObjectsDeleted  int
+	ReportsScrubbed bool
+	RequestedBy     string
+	CreatedAt       time.Time
+}
@@ -6,9 +6,56 @@ import (
 	"github.com/google/uuid"
 )
 
+type SubmissionStatus string
+
+const (
+	SubmissionStatusActive SubmissionStatus = "active"
+	// SubmissionStatusFileMissing marks rows whose object is gone from the
+	// storage, as found by reconciliation.
+	SubmissionStatusFileMissing SubmissionStatus = "file_missing"
+	// SubmissionStatusQuarantined marks files flagged by the malware
+	// scanner; they are kept but never served or checked.
+	SubmissionStatusQuarantined SubmissionStatus = "quarantined"
+)
+
 type Submission struct {
 	SubmissionID uuid.UUID
 	AssignmentID string
 	AuthorID     string
 	CreatedAt    time.Time
+	SizeBytes    int64
+	Checksum     string
+	DeletedAt    *time.Time
+	Status       SubmissionStatus
+	// ContentType is the MIME type sniffed from the file on upload; empty
+	// for submissions stored before sniffing existed.
+	ContentType string
+	// OriginalFilename is the name the file was uploaded under; empty for
+	// submissions stored before it was recorded.
+	OriginalFilename string
+}
+
+// FileInfo is what becomes known about a submission once its file is stored.
+type FileInfo struct {
+	SizeBytes        int64
+	Checksum         string
+	ContentType      string
+	Status           SubmissionStatus
+	OriginalFilename string
+}
+
+// ScanResult is a malware scanner's verdict on an uploaded file.
+type ScanResult struct {
+	Infected bool
+	// Signature names what was found, when Infected.
+	Signature string
+}
+
+// Usage is how much of their quota an author has used.
+type Usage struct {
+	// AssignmentSubmissions counts the author's live submissions to one
+	// assignment.
+	AssignmentSubmissions int64
+	// BytesUsed sums the sizes of all the author's live submissions.
+	BytesUsed int64
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"filestorage/internal/domain/entity"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores key unless its scope already has that key, and returns
+	// the stored key and whether it is the new one.
+	Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error)
+
+	// Complete records the response to the request that reserved the key.
+	Complete(ctx context.Context, scope, key string, statusCode int, body []byte) error
+
+	// Release drops a key whose request has not completed, so that a retry
+	// can run the request again.
+	Release(ctx context.Context, scope, key string) error
+
+	// DeleteExpired removes keys created before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"filestorage/internal/domain/entity"
+)
+
+type OutboxRepository interface {
+	// AddWithTx stores the event in a transaction opened by
+	// SubmissionRepository, so it exists only if that transaction commits.
+	AddWithTx(ctx context.Context, tx Transaction, event *entity.OutboxEvent) error
+
+	// Claim returns up to limit events that are due and hides them from other
+	// claims until leaseUntil, counting the attempt.
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.OutboxEvent, error)
+
+	// Delete removes a delivered event.
+	Delete(ctx context.Context, id int64) error
+
+	// Retry schedules a failed event for another attempt at availableAt.
+	Retry(ctx context.Context, id int64, availableAt time.Time, lastError string) error
+
+	// Release makes the held events of the assignment due at availableAt.
+	Release(ctx context.Context, assignmentID string, availableAt time.Time) (int64, error)
+}
@@ -3,12 +3,37 @@ package repository
 import (
 	"context"
 	"io"
+	"time"
 )
 
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
 type S3Repository interface {
-	UploadFile(ctx context.Context, key string, data []byte, contentType string) error
+	UploadFile(ctx context.Context, key string, body io.Reader, contentType string) error
 
 	GetFile(ctx context.Context, key string) (io.ReadCloser, error)
 
+	// GetFileRange reads length bytes starting at offset; a negative length
+	// reads to the end of the object.
+	GetFileRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+
 	DeleteFile(ctx context.Context, key string) error
+
+	ListObjects(ctx context.Context) ([]ObjectInfo, error)
+}
+
+// Presigner is implemented by object stores that can hand out time-limited
+// URLs, letting clients fetch objects without going through this service.
+type Presigner interface {
+	PresignGetFile(ctx context.Context, key, filename string, ttl time.Duration) (string, error)
+}
+
+// KeyRotator is implemented by object stores that encrypt per-object data
+// keys under a master key; RotateKeys re-wraps them with the active one.
+type KeyRotator interface {
+	RotateKeys(ctx context.Context) (rewrapped, current int, err error)
 }
@@ -2,22 +2,78 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"filestorage/internal/domain/entity"
 
 	"github.com/google/uuid"
 )
 
+type SortOrder string
+
+const (
+	SortCreatedAtDesc SortOrder = "created_at_desc"
+	SortCreatedAtAsc  SortOrder = "created_at_asc"
+)
+
+type Cursor struct {
+	CreatedAt    time.Time
+	SubmissionID uuid.UUID
+}
+
+type ListFilter struct {
+	AssignmentID  string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          SortOrder
+	After         *Cursor
+	Limit         int
+}
+
 type SubmissionRepository interface {
 	Create(ctx context.Context, assignmentID, authorID string) (*entity.Submission, error)
 
 	CreateWithTx(ctx context.Context, assignmentID, authorID string) (*entity.Submission, Transaction, error)
 
+	// CreateAtWithTx is CreateWithTx with an explicit creation time, for
+	// submissions imported with their original timestamps.
+	CreateAtWithTx(ctx context.Context, assignmentID, authorID string, createdAt time.Time) (*entity.Submission, Transaction, error)
+
+	SetFileInfoWithTx(ctx context.Context, tx Transaction, submissionID uuid.UUID, info entity.FileInfo) error
+
 	GetByID(ctx context.Context, submissionID uuid.UUID) (*entity.Submission, error)
 
-	GetByAssignmentID(ctx context.Context, assignmentID string) ([]*entity.Submission, error)
+	ListByAssignmentID(ctx context.Context, filter ListFilter) ([]*entity.Submission, error)
 
 	GetByAuthorID(ctx context.Context, authorID string) ([]*entity.Submission, error)
+
+	// GetUsage counts the author's live submissions to assignmentID and the
+	// bytes taken by all of the author's live submissions.
+	GetUsage(ctx context.Context, authorID, assignmentID string) (*entity.Usage, error)
+
+	// SoftDelete marks the submission as deleted and returns it. A non-empty
+	// assignmentID restricts the lookup to that assignment. Deleting an already
+	// deleted submission is not an error, so cleanup can be retried.
+	SoftDelete(ctx context.Context, submissionID uuid.UUID, assignmentID string) (*entity.Submission, error)
+
+	// SoftDeleteWithTx is SoftDelete inside a transaction the caller commits,
+	// so that events about the deletion can be stored with it.
+	SoftDeleteWithTx(ctx context.Context, submissionID uuid.UUID, assignmentID string) (*entity.Submission, Transaction, error)
+
+	// ListAfterID pages through all rows, soft-deleted included, in
+	// submission_id order. Pass uuid.Nil to start from the beginning.
+	ListAfterID(ctx context.Context, after uuid.UUID, limit int) ([]*entity.Submission, error)
+
+	SetStatus(ctx context.Context, submissionID uuid.UUID, status entity.SubmissionStatus) error
+
+	// ListAllByAuthorID returns every submission of the author, including
+	// soft-deleted ones.
+	ListAllByAuthorID(ctx context.Context, authorID string) ([]*entity.Submission, error)
+
+	// PurgeByAuthorID removes the author's rows listed in audit.SubmissionIDs
+	// for good and stores the audit record in the same transaction. Rows
+	// created after the list was taken are kept, as their objects are.
+	PurgeByAuthorID(ctx context.Context, authorID string, audit *entity.PurgeAudit) (*entity.PurgeAudit, error)
 }
 
 type Transaction interface {
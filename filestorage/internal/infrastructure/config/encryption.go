@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type EncryptionConfig struct {
+	// Keys maps key ids to 32-byte master keys. Empty disables encryption.
+	Keys        map[string][]byte
+	ActiveKeyID string
+}
+
+func (c *EncryptionConfig) Enabled() bool {
+	return len(c.Keys) > 0
+}
+
+// LoadEncryptionConfig reads ENCRYPTION_KEYS as "id:base64key,..." and
+// ENCRYPTION_ACTIVE_KEY, which defaults to the first listed id. Unlike the
+// other settings a malformed value is an error: silently falling back would
+// store plaintext or make existing objects unreadable.
+func LoadEncryptionConfig() (*EncryptionConfig, error) {
+	cfg := &EncryptionConfig{Keys: make(map[string][]byte)}
+
+	raw := os.Getenv("ENCRYPTION_KEYS")
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("ENCRYPTION_KEYS: expected id:base64key, got %q", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("ENCRYPTION_KEYS: key %q is not valid base64: %w", id, err)
+		}
+		if _, dup := cfg.Keys[id]; dup {
+			return nil, fmt.Errorf("ENCRYPTION_KEYS: duplicate key id %q", id)
+		}
+		cfg.Keys[id] = key
+		if cfg.ActiveKeyID == "" {
+			cfg.ActiveKeyID = id
+		}
+	}
+
+	if active := os.Getenv("ENCRYPTION_ACTIVE_KEY"); active != "" {
+		cfg.ActiveKeyID = active
+	}
+	if cfg.Enabled() {
+		if _, ok := cfg.Keys[cfg.ActiveKeyID]; !ok {
+			return nil, fmt.Errorf("ENCRYPTION_ACTIVE_KEY %q is not in ENCRYPTION_KEYS", cfg.ActiveKeyID)
+		}
+	}
+	return cfg, nil
+}
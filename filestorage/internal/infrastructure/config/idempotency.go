@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+const defaultIdempotencyTTL = 24 * time.Hour
+
+type IdempotencyConfig struct {
+	// TTL is how long a key replays its first response; a key used again
+	// after that starts a new request.
+	TTL time.Duration
+}
+
+func LoadIdempotencyConfig() *IdempotencyConfig {
+	cfg := &IdempotencyConfig{
+		TTL: durationEnv("IDEMPOTENCY_TTL", defaultIdempotencyTTL),
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultIdempotencyTTL
+	}
+	return cfg
+}
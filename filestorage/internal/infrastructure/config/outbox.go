@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+const (
+	defaultOutboxPollInterval = 2 * time.Second
+	defaultOutboxMaxBackoff   = 5 * time.Minute
+)
+
+type OutboxConfig struct {
+	// PollInterval is how often due events are looked for when nothing
+	// wakes the dispatcher earlier.
+	PollInterval time.Duration
+	// MaxBackoff caps the delay between delivery attempts of one event.
+	MaxBackoff time.Duration
+}
+
+func LoadOutboxConfig() *OutboxConfig {
+	cfg := &OutboxConfig{
+		PollInterval: durationEnv("OUTBOX_POLL_INTERVAL", defaultOutboxPollInterval),
+		MaxBackoff:   durationEnv("OUTBOX_MAX_BACKOFF", defaultOutboxMaxBackoff),
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultOutboxPollInterval
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultOutboxMaxBackoff
+	}
+	return cfg
+}
@@ -0,0 +1,7 @@
+package config
+
+// PlagiarismURL is where deletion notifications go and where exports fetch
+// reports from. Empty disables both.
+func PlagiarismURL() string {
+	return getEnv("PLAGIARISM_URL", "")
+}
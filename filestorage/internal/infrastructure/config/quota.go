@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// QuotaConfig limits what a single author may store. Zero means unlimited.
+type QuotaConfig struct {
+	MaxSubmissionsPerAssignment int64
+	MaxBytesPerAuthor           int64
+}
+
+func LoadQuotaConfig() *QuotaConfig {
+	return &QuotaConfig{
+		MaxSubmissionsPerAssignment: int64Env("QUOTA_MAX_SUBMISSIONS_PER_ASSIGNMENT", 0),
+		MaxBytesPerAuthor:           int64Env("QUOTA_MAX_BYTES_PER_AUTHOR", 0),
+	}
+}
+
+func int64Env(key string, defaultValue int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultReconcileGrace = time.Hour
+
+type ReconcileConfig struct {
+	// Interval between background runs; zero disables them.
+	Interval time.Duration
+	Repair   bool
+	Grace    time.Duration
+}
+
+func LoadReconcileConfig() *ReconcileConfig {
+	return &ReconcileConfig{
+		Interval: durationEnv("RECONCILE_INTERVAL", 0),
+		Repair:   boolEnv("RECONCILE_REPAIR", false),
+		Grace:    durationEnv("RECONCILE_GRACE", defaultReconcileGrace),
+	}
+}
+
+func durationEnv(key string, defaultValue time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func boolEnv(key string, defaultValue bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
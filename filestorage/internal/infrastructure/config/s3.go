@@ -1,18 +1,30 @@
 package config
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 type S3Config struct {
 	Bucket   string
 	Endpoint string
 	Region   string
+	// PublicEndpoint is the host baked into presigned URLs. Clients outside
+	// the compose network cannot reach Endpoint, so it may differ.
+	PublicEndpoint string
+	// PresignTTL is how long presigned download URLs stay valid; zero turns
+	// presigning off and downloads are always proxied.
+	PresignTTL time.Duration
 }
 
 func LoadS3Config() *S3Config {
+	endpoint := getEnv("S3_ENDPOINT", "")
 	return &S3Config{
-		Bucket:   getEnv("S3_BUCKET", "filestorage"),
-		Endpoint: getEnv("S3_ENDPOINT", ""),
-		Region:   getEnv("AWS_REGION", "us-east-1"),
+		Bucket:         getEnv("S3_BUCKET", "filestorage"),
+		Endpoint:       endpoint,
+		Region:         getEnv("AWS_REGION", "us-east-1"),
+		PublicEndpoint: getEnv("S3_PUBLIC_ENDPOINT", endpoint),
+		PresignTTL:     durationEnv("PRESIGN_TTL", 5*time.Minute),
 	}
 }
 
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+const (
+	ScannerBackendNone  = "none"
+	ScannerBackendClamd = "clamd"
+)
+
+type ScannerConfig struct {
+	Backend string
+	// ClamdAddress is tcp://host:port, unix:///path or a bare host:port.
+	ClamdAddress string
+	ClamdTimeout time.Duration
+}
+
+func LoadScannerConfig() *ScannerConfig {
+	return &ScannerConfig{
+		Backend:      getEnv("SCANNER_BACKEND", ScannerBackendNone),
+		ClamdAddress: getEnv("CLAMD_ADDRESS", "tcp://localhost:3310"),
+		ClamdTimeout: durationEnv("CLAMD_TIMEOUT", 30*time.Second),
+	}
+}
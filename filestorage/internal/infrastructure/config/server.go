@@ -3,3 +3,8 @@ package config
 func ServerPort() string {
 	return getEnv("PORT", "8080")
 }
+
+// AdminToken guards the /admin routes. Empty disables them.
+func AdminToken() string {
+	return getEnv("ADMIN_TOKEN", "")
+}
@@ -0,0 +1,19 @@
+package config
+
+const (
+	StorageBackendS3     = "s3"
+	StorageBackendFS     = "fs"
+	StorageBackendMemory = "memory"
+)
+
+type StorageConfig struct {
+	Backend string
+	FSRoot  string
+}
+
+func LoadStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Backend: getEnv("STORAGE_BACKEND", StorageBackendS3),
+		FSRoot:  getEnv("STORAGE_FS_ROOT", "data/objects"),
+	}
+}
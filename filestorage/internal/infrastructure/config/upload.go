@@ -5,7 +5,10 @@ import (
 	"strconv"
 )
 
-const defaultMaxUploadSize = 1 * 1024 * 1024
+const (
+	defaultMaxUploadSize = 1 * 1024 * 1024
+	defaultMaxImportSize = 256 * 1024 * 1024
+)
 
 func MaxUploadSize() int64 {
 	if v := os.Getenv("MAX_UPLOAD_SIZE_BYTES"); v != "" {
@@ -15,3 +18,13 @@ func MaxUploadSize() int64 {
 	}
 	return defaultMaxUploadSize
 }
+
+// MaxImportSize bounds an import archive, which is spooled to disk whole.
+func MaxImportSize() int64 {
+	if v := os.Getenv("MAX_IMPORT_SIZE_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultMaxImportSize
+}
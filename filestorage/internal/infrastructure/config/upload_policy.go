@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AllowList restricts what may be uploaded. Empty lists allow anything;
+// when both are set a file must match both.
+type AllowList struct {
+	// Extensions like ".py", compared case-insensitively.
+	Extensions []string `json:"extensions"`
+	// MIMETypes like "text/plain" or "image/*", matched against the type
+	// sniffed from the content.
+	MIMETypes []string `json:"mime_types"`
+}
+
+type UploadPolicy struct {
+	Default     AllowList            `json:"default"`
+	Assignments map[string]AllowList `json:"assignments"`
+}
+
+// LoadUploadPolicy reads the JSON file named by UPLOAD_POLICY_FILE. Without
+// it every type is allowed, except executables which are always rejected.
+func LoadUploadPolicy() (*UploadPolicy, error) {
+	policy := &UploadPolicy{}
+
+	path := os.Getenv("UPLOAD_POLICY_FILE")
+	if path == "" {
+		return policy, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("UPLOAD_POLICY_FILE: %w", err)
+	}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("UPLOAD_POLICY_FILE %s: %w", path, err)
+	}
+
+	policy.Default = normalizeAllowList(policy.Default)
+	for id, list := range policy.Assignments {
+		policy.Assignments[id] = normalizeAllowList(list)
+	}
+	return policy, nil
+}
+
+func normalizeAllowList(list AllowList) AllowList {
+	for i, ext := range list.Extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext != "" && !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		list.Extensions[i] = ext
+	}
+	for i, mimeType := range list.MIMETypes {
+		list.MIMETypes[i] = strings.ToLower(strings.TrimSpace(mimeType))
+	}
+	return list
+}
@@ -0,0 +1,177 @@
+package plagiarism
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"filestorage/internal/domain/entity"
+)
+
+const (
+	purgesPath      = "/purges"
+	batchChecksPath = "/checks/batch"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) SubmissionDeleted(ctx context.Context, assignmentID, submissionID string) error {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid plagiarism url: %w", err)
+	}
+	u.Path = fmt.Sprintf("/works/%s/submissions/%s", url.PathEscape(assignmentID), url.PathEscape(submissionID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("notify plagiarism: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+type purgeSubmission struct {
+	WorkID       string `json:"work_id"`
+	SubmissionID string `json:"submission_id"`
+}
+
+func (c *Client) AuthorPurged(ctx context.Context, authorID string, submissions []*entity.Submission) error {
+	payload := struct {
+		AuthorID    string            `json:"author_id"`
+		Submissions []purgeSubmission `json:"submissions"`
+	}{
+		AuthorID:    authorID,
+		Submissions: make([]purgeSubmission, 0, len(submissions)),
+	}
+	for _, sub := range submissions {
+		payload.Submissions = append(payload.Submissions, purgeSubmission{
+			WorkID:       sub.AssignmentID,
+			SubmissionID: sub.SubmissionID.String(),
+		})
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid plagiarism url: %w", err)
+	}
+	u.Path = purgesPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("purge plagiarism reports: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (c *Client) WorkReports(ctx context.Context, assignmentID string) ([]byte, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plagiarism url: %w", err)
+	}
+	u.Path = fmt.Sprintf("/works/%s/reports", url.PathEscape(assignmentID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("get plagiarism reports: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("get plagiarism reports: invalid json")
+	}
+	return body, nil
+}
+
+func (c *Client) StartChecks(ctx context.Context, assignmentID string, submissionIDs []string) error {
+	body, err := json.Marshal(struct {
+		WorkID        string   `json:"work_id"`
+		SubmissionIDs []string `json:"submission_ids"`
+	}{
+		WorkID:        assignmentID,
+		SubmissionIDs: submissionIDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid plagiarism url: %w", err)
+	}
+	u.Path = batchChecksPath
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("start plagiarism checks: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
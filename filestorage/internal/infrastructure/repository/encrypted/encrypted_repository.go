@@ -0,0 +1,263 @@
+package encrypted
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"strings"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/repository"
+)
+
+// keySuffix names the sidecar object holding an object's wrapped data key.
+// Keeping it apart from the data means rotation rewrites a few hundred
+// bytes per object instead of the objects themselves.
+const keySuffix = ".dek"
+
+const dataKeySize = 32
+
+type keyRecord struct {
+	KeyID      string `json:"key_id"`
+	WrappedKey []byte `json:"wrapped_key"`
+	ChunkSize  int    `json:"chunk_size"`
+}
+
+type encryptedRepository struct {
+	inner   repository.S3Repository
+	keyring *Keyring
+}
+
+// NewEncryptedRepository wraps an object store with envelope encryption.
+// Objects written before encryption was enabled have no data key and are
+// still returned as stored.
+func NewEncryptedRepository(inner repository.S3Repository, keyring *Keyring) repository.S3Repository {
+	return &encryptedRepository{inner: inner, keyring: keyring}
+}
+
+// UploadFile stores the wrapped data key first, so a data object never
+// exists without the key needed to read it.
+func (r *encryptedRepository) UploadFile(ctx context.Context, key string, body io.Reader, _ string) error {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return apperr.Wrap(err, apperr.CodeInternal, "failed to generate data key")
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeInternal, "failed to init cipher")
+	}
+
+	record := keyRecord{ChunkSize: defaultChunkSize}
+	record.KeyID, record.WrappedKey, err = r.keyring.wrap(dataKey, key)
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeInternal, "failed to wrap data key")
+	}
+	if err := r.putKeyRecord(ctx, key, record); err != nil {
+		return err
+	}
+
+	if err := r.inner.UploadFile(ctx, key, newEncryptReader(body, aead, record.ChunkSize), "application/octet-stream"); err != nil {
+		_ = r.inner.DeleteFile(ctx, key+keySuffix)
+		return err
+	}
+	return nil
+}
+
+func (r *encryptedRepository) GetFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	return r.GetFileRange(ctx, key, 0, -1)
+}
+
+// GetFileRange fetches only the chunks covering the range, plus one byte
+// past them to learn whether the last of them is final.
+func (r *encryptedRepository) GetFileRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	record, err := r.getKeyRecord(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		if offset == 0 && length < 0 {
+			return r.inner.GetFile(ctx, key)
+		}
+		return r.inner.GetFileRange(ctx, key, offset, length)
+	}
+	if length == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	aead, err := r.dataCipher(key, record)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := int64(record.ChunkSize)
+	sealedSize := chunkSize + int64(aead.Overhead())
+	first := offset / chunkSize
+	sealedLength := int64(-1)
+	if length > 0 {
+		last := (offset + length - 1) / chunkSize
+		sealedLength = (last-first+1)*sealedSize + 1
+	}
+
+	var sealed io.ReadCloser
+	if first == 0 && sealedLength < 0 {
+		sealed, err = r.inner.GetFile(ctx, key)
+	} else {
+		sealed, err = r.inner.GetFileRange(ctx, key, first*sealedSize, sealedLength)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plain := newDecryptReader(sealed, aead, record.ChunkSize, uint32(first))
+	if skip := offset - first*chunkSize; skip > 0 {
+		if _, err := io.CopyN(io.Discard, plain, skip); err != nil {
+			plain.Close()
+			return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to decrypt object")
+		}
+	}
+	if length < 0 {
+		return &decryptErrorReader{plain}, nil
+	}
+	return &decryptErrorReader{struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(plain, length), plain}}, nil
+}
+
+// DeleteFile drops the data key before the data: once the key is gone the
+// ciphertext is unreadable even if deleting it fails.
+func (r *encryptedRepository) DeleteFile(ctx context.Context, key string) error {
+	if err := r.inner.DeleteFile(ctx, key+keySuffix); err != nil && !apperr.IsCode(err, apperr.CodeNotFound) {
+		return err
+	}
+	return r.inner.DeleteFile(ctx, key)
+}
+
+// ListObjects hides data key sidecars, except those whose object is gone:
+// they are listed under their own key so that reconciliation reports them
+// as orphans, and deleting that key removes the sidecar.
+func (r *encryptedRepository) ListObjects(ctx context.Context) ([]repository.ObjectInfo, error) {
+	objects, err := r.inner.ListObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]struct{}, len(objects))
+	for _, obj := range objects {
+		if !strings.HasSuffix(obj.Key, keySuffix) {
+			data[obj.Key] = struct{}{}
+		}
+	}
+
+	filtered := objects[:0]
+	for _, obj := range objects {
+		if key, ok := strings.CutSuffix(obj.Key, keySuffix); ok {
+			if _, hasData := data[key]; hasData {
+				continue
+			}
+		}
+		filtered = append(filtered, obj)
+	}
+	return filtered, nil
+}
+
+// RotateKeys re-wraps every data key that is not under the active master
+// key. Object data is not touched.
+func (r *encryptedRepository) RotateKeys(ctx context.Context) (rewrapped, current int, err error) {
+	objects, err := r.inner.ListObjects(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, obj := range objects {
+		key, ok := strings.CutSuffix(obj.Key, keySuffix)
+		if !ok {
+			continue
+		}
+		record, err := r.getKeyRecord(ctx, key)
+		if err != nil {
+			return rewrapped, current, err
+		}
+		if record == nil {
+			// Deleted since it was listed.
+			continue
+		}
+		if record.KeyID == r.keyring.activeID {
+			current++
+			continue
+		}
+
+		dataKey, err := r.keyring.unwrap(record.KeyID, record.WrappedKey, key)
+		if err != nil {
+			return rewrapped, current, apperr.Wrap(err, apperr.CodeInternal, "failed to unwrap data key of "+key)
+		}
+		record.KeyID, record.WrappedKey, err = r.keyring.wrap(dataKey, key)
+		if err != nil {
+			return rewrapped, current, apperr.Wrap(err, apperr.CodeInternal, "failed to wrap data key of "+key)
+		}
+		if err := r.putKeyRecord(ctx, key, *record); err != nil {
+			return rewrapped, current, err
+		}
+		rewrapped++
+	}
+	return rewrapped, current, nil
+}
+
+func (r *encryptedRepository) dataCipher(key string, record *keyRecord) (cipher.AEAD, error) {
+	dataKey, err := r.keyring.unwrap(record.KeyID, record.WrappedKey, key)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "failed to unwrap data key")
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "failed to init cipher")
+	}
+	return aead, nil
+}
+
+// getKeyRecord returns nil when the object has no data key, i.e. it was
+// stored in plaintext.
+func (r *encryptedRepository) getKeyRecord(ctx context.Context, key string) (*keyRecord, error) {
+	body, err := r.inner.GetFile(ctx, key+keySuffix)
+	if err != nil {
+		if apperr.IsCode(err, apperr.CodeNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer body.Close()
+
+	var record keyRecord
+	if err := json.NewDecoder(body).Decode(&record); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to read data key")
+	}
+	if record.ChunkSize <= 0 {
+		return nil, apperr.New(apperr.CodeStorage, "invalid data key record")
+	}
+	return &record, nil
+}
+
+func (r *encryptedRepository) putKeyRecord(ctx context.Context, key string, record keyRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeInternal, "failed to encode data key")
+	}
+	return r.inner.UploadFile(ctx, key+keySuffix, bytes.NewReader(data), "application/json")
+}
+
+// decryptErrorReader reports decryption failures as storage errors, like
+// any other failure to read an object.
+type decryptErrorReader struct {
+	io.ReadCloser
+}
+
+func (r *decryptErrorReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = apperr.Wrap(err, apperr.CodeStorage, "failed to decrypt object")
+	}
+	return n, err
+}
@@ -0,0 +1,72 @@
+package encrypted
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+const masterKeySize = 32
+
+// Keyring holds the master keys that wrap per-object data keys. Only the
+// active key wraps new data keys; the others stay to unwrap older ones
+// until rotation has moved everything over.
+type Keyring struct {
+	keys     map[string]cipher.AEAD
+	activeID string
+}
+
+func NewKeyring(keys map[string][]byte, activeID string) (*Keyring, error) {
+	ring := &Keyring{keys: make(map[string]cipher.AEAD, len(keys)), activeID: activeID}
+	for id, key := range keys {
+		if len(key) != masterKeySize {
+			return nil, fmt.Errorf("master key %q must be %d bytes, got %d", id, masterKeySize, len(key))
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		ring.keys[id] = aead
+	}
+	if _, ok := ring.keys[activeID]; !ok {
+		return nil, fmt.Errorf("active master key %q is not in the keyring", activeID)
+	}
+	return ring, nil
+}
+
+// wrap encrypts a data key under the active master key. The object key is
+// bound as additional data so a sidecar cannot be swapped onto another
+// object.
+func (k *Keyring) wrap(dataKey []byte, objectKey string) (keyID string, wrapped []byte, err error) {
+	aead := k.keys[k.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return k.activeID, aead.Seal(nonce, nonce, dataKey, []byte(objectKey)), nil
+}
+
+func (k *Keyring) unwrap(keyID string, wrapped []byte, objectKey string) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q is not configured", keyID)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped data key is truncated")
+	}
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(objectKey))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key with %q: %w", keyID, err)
+	}
+	return dataKey, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
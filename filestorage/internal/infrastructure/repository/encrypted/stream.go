@@ -0,0 +1,153 @@
+package encrypted
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Objects are sealed in fixed-size chunks with AES-GCM so they can be
+// streamed and read by range without holding the whole file. Every object
+// has its own data key, so the nonce can be derived from the chunk index;
+// its last byte flags the final chunk, which makes truncation at a chunk
+// boundary detectable (the STREAM construction).
+
+const defaultChunkSize = 64 << 10
+
+var errTruncated = errors.New("encrypted object is truncated")
+
+func chunkNonce(aead cipher.AEAD, counter uint32, final bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint32(nonce[len(nonce)-5:], counter)
+	if final {
+		nonce[len(nonce)-1] = 1
+	}
+	return nonce
+}
+
+type encryptReader struct {
+	src       *bufio.Reader
+	aead      cipher.AEAD
+	chunkSize int
+	counter   uint32
+	plain     []byte
+	out       []byte
+	done      bool
+}
+
+func newEncryptReader(src io.Reader, aead cipher.AEAD, chunkSize int) *encryptReader {
+	return &encryptReader{
+		src:       bufio.NewReader(src),
+		aead:      aead,
+		chunkSize: chunkSize,
+		plain:     make([]byte, chunkSize),
+	}
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *encryptReader) sealNext() error {
+	n, err := io.ReadFull(r.src, r.plain)
+	final := false
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		return err
+	default:
+		// A full chunk is final only if nothing follows it.
+		if _, err := r.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	r.out = r.aead.Seal(r.out[:0], chunkNonce(r.aead, r.counter, final), r.plain[:n], nil)
+	r.counter++
+	r.done = final
+	return nil
+}
+
+type decryptReader struct {
+	src     *bufio.Reader
+	closer  io.Closer
+	aead    cipher.AEAD
+	counter uint32
+	sealed  []byte
+	out     []byte
+	done    bool
+}
+
+// newDecryptReader decrypts src starting at chunk index first. Callers that
+// read a range stop before the end of src; the extra byte they fetch past
+// the last chunk only tells that chunk apart from the final one.
+func newDecryptReader(src io.ReadCloser, aead cipher.AEAD, chunkSize int, first uint32) *decryptReader {
+	return &decryptReader{
+		src:     bufio.NewReader(src),
+		closer:  src,
+		aead:    aead,
+		counter: first,
+		sealed:  make([]byte, chunkSize+aead.Overhead()),
+	}
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.openNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *decryptReader) openNext() error {
+	n, err := io.ReadFull(r.src, r.sealed)
+	final := false
+	switch {
+	case err == io.EOF:
+		return errTruncated
+	case err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plain, err := r.aead.Open(r.out[:0], chunkNonce(r.aead, r.counter, final), r.sealed[:n], nil)
+	if err != nil {
+		return errors.New("encrypted object failed authentication")
+	}
+	r.out = plain
+	r.counter++
+	r.done = final
+	return nil
+}
+
+func (r *decryptReader) Close() error {
+	return r.closer.Close()
+}
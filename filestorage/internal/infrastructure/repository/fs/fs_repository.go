@@ -0,0 +1,160 @@
+package fs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/repository"
+)
+
+type fsRepository struct {
+	root string
+}
+
+func NewFSRepository(root string) (repository.S3Repository, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &fsRepository{root: root}, nil
+}
+
+func (r *fsRepository) objectPath(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", apperr.New(apperr.CodeStorage, "invalid object key")
+	}
+	return filepath.Join(r.root, filepath.FromSlash(key)), nil
+}
+
+// UploadFile writes into a temporary file next to the target and renames it
+// into place, so readers never observe a partially written object.
+func (r *fsRepository) UploadFile(_ context.Context, key string, body io.Reader, _ string) error {
+	path, err := r.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to upload object")
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to upload object")
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		if tmpName != "" {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to upload object")
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to upload object")
+	}
+	if err := tmp.Close(); err != nil {
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to upload object")
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to upload object")
+	}
+	tmpName = ""
+
+	return nil
+}
+
+func (r *fsRepository) GetFile(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := r.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apperr.Wrap(err, apperr.CodeNotFound, "object not found")
+		}
+		return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to get object")
+	}
+
+	return file, nil
+}
+
+func (r *fsRepository) GetFileRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	rc, err := r.GetFile(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	file := rc.(*os.File)
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to get object range")
+	}
+	if length < 0 {
+		return file, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(file, length), file}, nil
+}
+
+func (r *fsRepository) DeleteFile(_ context.Context, key string) error {
+	path, err := r.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to delete object")
+	}
+	return nil
+}
+
+// ListObjects walks the root directory. In-flight uploads (temporary files)
+// are not objects yet and are skipped.
+func (r *fsRepository) ListObjects(_ context.Context) ([]repository.ObjectInfo, error) {
+	var objects []repository.ObjectInfo
+
+	err := filepath.WalkDir(r.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		key, err := filepath.Rel(r.root, path)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, repository.ObjectInfo{
+			Key:          filepath.ToSlash(key),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to list objects")
+	}
+
+	return objects, nil
+}
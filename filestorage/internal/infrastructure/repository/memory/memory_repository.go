@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/repository"
+)
+
+type memoryObject struct {
+	data         []byte
+	lastModified time.Time
+}
+
+type memoryRepository struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewMemoryRepository() repository.S3Repository {
+	return &memoryRepository{objects: make(map[string]memoryObject)}
+}
+
+func (r *memoryRepository) UploadFile(_ context.Context, key string, body io.Reader, _ string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeStorage, "failed to upload object")
+	}
+
+	r.mu.Lock()
+	r.objects[key] = memoryObject{data: data, lastModified: time.Now()}
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *memoryRepository) GetFile(_ context.Context, key string) (io.ReadCloser, error) {
+	r.mu.RLock()
+	obj, ok := r.objects[key]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, apperr.New(apperr.CodeNotFound, "object not found")
+	}
+	return io.NopCloser(bytes.NewReader(obj.data)), nil
+}
+
+func (r *memoryRepository) GetFileRange(_ context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	r.mu.RLock()
+	obj, ok := r.objects[key]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, apperr.New(apperr.CodeNotFound, "object not found")
+	}
+
+	data := obj.data[min(offset, int64(len(obj.data))):]
+	if length >= 0 && length < int64(len(data)) {
+		data = data[:length]
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (r *memoryRepository) DeleteFile(_ context.Context, key string) error {
+	r.mu.Lock()
+	delete(r.objects, key)
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *memoryRepository) ListObjects(_ context.Context) ([]repository.ObjectInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	objects := make([]repository.ObjectInfo, 0, len(r.objects))
+	for key, obj := range r.objects {
+		objects = append(objects, repository.ObjectInfo{
+			Key:          key,
+			Size:         int64(len(obj.data)),
+			LastModified: obj.lastModified,
+		})
+	}
+	return objects, nil
+}
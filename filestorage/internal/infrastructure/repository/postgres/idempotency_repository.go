@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresIdempotencyRepository struct {
+	queries *Queries
+}
+
+func NewPostgresIdempotencyRepository(pool *pgxpool.Pool) repository.IdempotencyRepository {
+	return &postgresIdempotencyRepository{queries: New(pool)}
+}
+
+func (r *postgresIdempotencyRepository) Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error) {
+	// The key found taken may be released before it is read; the insert is
+	// then tried once more.
+	for range 2 {
+		inserted, err := r.queries.ReserveIdempotencyKey(ctx, ReserveIdempotencyKeyParams{
+			Scope:          key.Scope,
+			IdempotencyKey: key.Key,
+			Fingerprint:    key.Fingerprint,
+			CreatedAt:      key.CreatedAt.UTC(),
+		})
+		if err != nil {
+			return nil, false, apperr.Wrap(err, apperr.CodeDatabase, "failed to reserve idempotency key")
+		}
+		if inserted == 1 {
+			return key, true, nil
+		}
+
+		stored, err := r.queries.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+			Scope:          key.Scope,
+			IdempotencyKey: key.Key,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, false, apperr.Wrap(err, apperr.CodeDatabase, "failed to load idempotency key")
+		}
+		return &entity.IdempotencyKey{
+			Scope:        stored.Scope,
+			Key:          stored.IdempotencyKey,
+			Fingerprint:  stored.Fingerprint,
+			StatusCode:   int(stored.StatusCode),
+			ResponseBody: stored.ResponseBody,
+			CreatedAt:    stored.CreatedAt,
+		}, false, nil
+	}
+	return nil, false, apperr.New(apperr.CodeDatabase, "failed to reserve idempotency key")
+}
+
+func (r *postgresIdempotencyRepository) Complete(ctx context.Context, scope, key string, statusCode int, body []byte) error {
+	err := r.queries.CompleteIdempotencyKey(ctx, CompleteIdempotencyKeyParams{
+		Scope:          scope,
+		IdempotencyKey: key,
+		StatusCode:     int32(statusCode),
+		ResponseBody:   body,
+	})
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to complete idempotency key")
+	}
+	return nil
+}
+
+func (r *postgresIdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	err := r.queries.ReleaseIdempotencyKey(ctx, ReleaseIdempotencyKeyParams{
+		Scope:          scope,
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to release idempotency key")
+	}
+	return nil
+}
+
+func (r *postgresIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := r.queries.DeleteExpiredIdempotencyKeys(ctx, before.UTC())
+	if err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeDatabase, "failed to delete expired idempotency keys")
+	}
+	return deleted, nil
+}
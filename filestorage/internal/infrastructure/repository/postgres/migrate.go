@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"filestorage/migrations"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockKey is the pg_advisory_lock key that serialises migration
+// runs of all filestorage replicas sharing a database.
+const migrationLockKey int64 = 0x66696c6573746f72 // "filestor"
+
+// Migrator applies the embedded Postgres migrations, each in its own
+// transaction, while holding a session advisory lock.
+type Migrator struct {
+	pool *pgxpool.Pool
+}
+
+func NewMigrator(pool *pgxpool.Pool) *Migrator {
+	return &Migrator{pool: pool}
+}
+
+// Up applies all pending migrations and returns them. Databases created by
+// the old docker-entrypoint-initdb.d mount have no schema_migrations yet;
+// every up file is idempotent, so they are simply replayed.
+func (m *Migrator) Up(ctx context.Context) ([]migrations.Migration, error) {
+	var done []migrations.Migration
+	err := m.locked(ctx, func(conn *pgxpool.Conn, all []migrations.Migration, applied map[int64]migrations.Applied) error {
+		for _, mig := range migrations.Pending(all, applied) {
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mig.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx,
+					`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					mig.Version, mig.Name,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("apply migration %s: %w", mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]migrations.Migration, error) {
+	var done []migrations.Migration
+	err := m.locked(ctx, func(conn *pgxpool.Conn, all []migrations.Migration, applied map[int64]migrations.Applied) error {
+		rollback, err := migrations.Rollback(all, applied, steps)
+		if err != nil {
+			return err
+		}
+		for _, mig := range rollback {
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mig.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("revert migration %s: %w", mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]migrations.Status, error) {
+	var result []migrations.Status
+	err := m.locked(ctx, func(_ *pgxpool.Conn, all []migrations.Migration, applied map[int64]migrations.Applied) error {
+		result = migrations.Statuses(all, applied)
+		return nil
+	})
+	return result, err
+}
+
+func (m *Migrator) locked(ctx context.Context, fn func(*pgxpool.Conn, []migrations.Migration, map[int64]migrations.Applied) error) error {
+	all, err := migrations.Load(migrations.Postgres, ".")
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	}()
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	applied, err := readApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return fn(conn, all, applied)
+}
+
+func readApplied(ctx context.Context, conn *pgxpool.Conn) (map[int64]migrations.Applied, error) {
+	rows, err := conn.Query(ctx, `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]migrations.Applied)
+	for rows.Next() {
+		var (
+			version int64
+			a       migrations.Applied
+		)
+		if err := rows.Scan(&version, &a.Name, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("read schema_migrations: %w", err)
+		}
+		applied[version] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	return applied, nil
+}
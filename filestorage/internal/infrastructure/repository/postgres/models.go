@@ -10,9 +10,45 @@ import (
 	"github.com/google/uuid"
 )
 
+type IdempotencyKey struct {
+	Scope          string    `json:"scope"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	Fingerprint    string    `json:"fingerprint"`
+	StatusCode     int32     `json:"status_code"`
+	ResponseBody   []byte    `json:"response_body"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type OutboxEvent struct {
+	ID           int64      `json:"id"`
+	EventType    string     `json:"event_type"`
+	AssignmentID string     `json:"assignment_id"`
+	SubmissionID uuid.UUID  `json:"submission_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	AvailableAt  *time.Time `json:"available_at"`
+	Attempts     int32      `json:"attempts"`
+	LastError    string     `json:"last_error"`
+}
+
+type PurgeAudit struct {
+	AuditID         uuid.UUID `json:"audit_id"`
+	AuthorHash      string    `json:"author_hash"`
+	SubmissionIds   string    `json:"submission_ids"`
+	ObjectsDeleted  int32     `json:"objects_deleted"`
+	ReportsScrubbed bool      `json:"reports_scrubbed"`
+	RequestedBy     string    `json:"requested_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
 type Submission struct {
-	SubmissionID uuid.UUID `json:"submission_id"`
-	AssignmentID string    `json:"assignment_id"`
-	AuthorID     string    `json:"author_id"`
-	CreatedAt    time.Time `json:"created_at"`
+	SubmissionID     uuid.UUID  `json:"submission_id"`
+	AssignmentID     string     `json:"assignment_id"`
+	AuthorID         string     `json:"author_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	SizeBytes        int64      `json:"size_bytes"`
+	Checksum         string     `json:"checksum"`
+	DeletedAt        *time.Time `json:"deleted_at"`
+	Status           string     `json:"status"`
+	ContentType      string     `json:"content_type"`
+	OriginalFilename string     `json:"original_filename"`
 }
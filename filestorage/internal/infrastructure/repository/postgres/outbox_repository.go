@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresOutboxRepository struct {
+	queries *Queries
+}
+
+func NewPostgresOutboxRepository(pool *pgxpool.Pool) repository.OutboxRepository {
+	return &postgresOutboxRepository{queries: New(pool)}
+}
+
+func toOutboxEntity(pgEvent OutboxEvent) *entity.OutboxEvent {
+	return &entity.OutboxEvent{
+		ID:           pgEvent.ID,
+		Type:         entity.OutboxEventType(pgEvent.EventType),
+		AssignmentID: pgEvent.AssignmentID,
+		SubmissionID: pgEvent.SubmissionID,
+		CreatedAt:    pgEvent.CreatedAt,
+		AvailableAt:  pgEvent.AvailableAt,
+		Attempts:     int(pgEvent.Attempts),
+		LastError:    pgEvent.LastError,
+	}
+}
+
+func utcPtr(t time.Time) *time.Time {
+	t = t.UTC()
+	return &t
+}
+
+func (r *postgresOutboxRepository) AddWithTx(ctx context.Context, tx repository.Transaction, event *entity.OutboxEvent) error {
+	pgTx, ok := tx.(*pgxTxWrapper)
+	if !ok {
+		return apperr.New(apperr.CodeDatabase, "unsupported transaction type")
+	}
+
+	var availableAt *time.Time
+	if event.AvailableAt != nil {
+		availableAt = utcPtr(*event.AvailableAt)
+	}
+	err := r.queries.WithTx(pgTx.tx).CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType:    string(event.Type),
+		AssignmentID: event.AssignmentID,
+		SubmissionID: event.SubmissionID,
+		AvailableAt:  availableAt,
+	})
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to store outbox event")
+	}
+	return nil
+}
+
+func (r *postgresOutboxRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	pgEvents, err := r.queries.ClaimOutboxEvents(ctx, ClaimOutboxEventsParams{
+		LeaseUntil: utcPtr(leaseUntil),
+		Now:        utcPtr(now),
+		PageLimit:  int32(limit),
+	})
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to claim outbox events")
+	}
+
+	events := make([]*entity.OutboxEvent, 0, len(pgEvents))
+	for _, pgEvent := range pgEvents {
+		events = append(events, toOutboxEntity(pgEvent))
+	}
+	return events, nil
+}
+
+func (r *postgresOutboxRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.queries.DeleteOutboxEvent(ctx, id); err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to delete outbox event")
+	}
+	return nil
+}
+
+func (r *postgresOutboxRepository) Retry(ctx context.Context, id int64, availableAt time.Time, lastError string) error {
+	err := r.queries.RetryOutboxEvent(ctx, RetryOutboxEventParams{
+		ID:          id,
+		AvailableAt: utcPtr(availableAt),
+		LastError:   lastError,
+	})
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to reschedule outbox event")
+	}
+	return nil
+}
+
+func (r *postgresOutboxRepository) Release(ctx context.Context, assignmentID string, availableAt time.Time) (int64, error) {
+	released, err := r.queries.ReleaseOutboxEvents(ctx, ReleaseOutboxEventsParams{
+		AssignmentID: assignmentID,
+		AvailableAt:  utcPtr(availableAt),
+	})
+	if err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeDatabase, "failed to release outbox events")
+	}
+	return released, nil
+}
@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	stdErrors "errors"
+	"time"
 
 	apperr "filestorage/internal/common/errors"
 	"filestorage/internal/domain/entity"
@@ -27,10 +29,16 @@ func NewPostgresRepository(pool *pgxpool.Pool) repository.SubmissionRepository {
 
 func toEntity(pgSub Submission) *entity.Submission {
 	return &entity.Submission{
-		SubmissionID: pgSub.SubmissionID,
-		AssignmentID: pgSub.AssignmentID,
-		AuthorID:     pgSub.AuthorID,
-		CreatedAt:    pgSub.CreatedAt,
+		SubmissionID:     pgSub.SubmissionID,
+		AssignmentID:     pgSub.AssignmentID,
+		AuthorID:         pgSub.AuthorID,
+		CreatedAt:        pgSub.CreatedAt,
+		SizeBytes:        pgSub.SizeBytes,
+		Checksum:         pgSub.Checksum,
+		DeletedAt:        pgSub.DeletedAt,
+		Status:           entity.SubmissionStatus(pgSub.Status),
+		ContentType:      pgSub.ContentType,
+		OriginalFilename: pgSub.OriginalFilename,
 	}
 }
 
@@ -75,6 +83,46 @@ func (r *postgresRepository) CreateWithTx(ctx context.Context, assignmentID, aut
 	return toEntity(pgSub), &pgxTxWrapper{tx: tx}, nil
 }
 
+func (r *postgresRepository) CreateAtWithTx(ctx context.Context, assignmentID, authorID string, createdAt time.Time) (*entity.Submission, repository.Transaction, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to begin submission tx")
+	}
+
+	queries := r.queries.WithTx(tx)
+	pgSub, err := queries.CreateSubmissionAt(ctx, CreateSubmissionAtParams{
+		AssignmentID: assignmentID,
+		AuthorID:     authorID,
+		CreatedAt:    createdAt.UTC(),
+	})
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to create submission")
+	}
+
+	return toEntity(pgSub), &pgxTxWrapper{tx: tx}, nil
+}
+
+func (r *postgresRepository) SetFileInfoWithTx(ctx context.Context, tx repository.Transaction, submissionID uuid.UUID, info entity.FileInfo) error {
+	pgTx, ok := tx.(*pgxTxWrapper)
+	if !ok {
+		return apperr.New(apperr.CodeDatabase, "unsupported transaction type")
+	}
+
+	err := r.queries.WithTx(pgTx.tx).UpdateSubmissionFileInfo(ctx, UpdateSubmissionFileInfoParams{
+		SubmissionID:     submissionID,
+		SizeBytes:        info.SizeBytes,
+		Checksum:         info.Checksum,
+		ContentType:      info.ContentType,
+		Status:           string(info.Status),
+		OriginalFilename: info.OriginalFilename,
+	})
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to update submission file info")
+	}
+	return nil
+}
+
 func (r *postgresRepository) GetByID(ctx context.Context, submissionID uuid.UUID) (*entity.Submission, error) {
 	pgSub, err := r.queries.GetSubmissionByID(ctx, submissionID)
 	if err != nil {
@@ -87,10 +135,41 @@ func (r *postgresRepository) GetByID(ctx context.Context, submissionID uuid.UUID
 	return toEntity(pgSub), nil
 }
 
-func (r *postgresRepository) GetByAssignmentID(ctx context.Context, assignmentID string) ([]*entity.Submission, error) {
-	pgSubs, err := r.queries.GetSubmissionsByAssignmentID(ctx, assignmentID)
+func (r *postgresRepository) ListByAssignmentID(ctx context.Context, filter repository.ListFilter) ([]*entity.Submission, error) {
+	var (
+		cursorCreatedAt *time.Time
+		cursorID        uuid.NullUUID
+	)
+	if filter.After != nil {
+		cursorCreatedAt = &filter.After.CreatedAt
+		cursorID = uuid.NullUUID{UUID: filter.After.SubmissionID, Valid: true}
+	}
+
+	var (
+		pgSubs []Submission
+		err    error
+	)
+	if filter.Sort == repository.SortCreatedAtAsc {
+		pgSubs, err = r.queries.ListSubmissionsByAssignmentIDAsc(ctx, ListSubmissionsByAssignmentIDAscParams{
+			AssignmentID:       filter.AssignmentID,
+			CreatedAfter:       filter.CreatedAfter,
+			CreatedBefore:      filter.CreatedBefore,
+			CursorCreatedAt:    cursorCreatedAt,
+			CursorSubmissionID: cursorID,
+			PageLimit:          int32(filter.Limit),
+		})
+	} else {
+		pgSubs, err = r.queries.ListSubmissionsByAssignmentIDDesc(ctx, ListSubmissionsByAssignmentIDDescParams{
+			AssignmentID:       filter.AssignmentID,
+			CreatedAfter:       filter.CreatedAfter,
+			CreatedBefore:      filter.CreatedBefore,
+			CursorCreatedAt:    cursorCreatedAt,
+			CursorSubmissionID: cursorID,
+			PageLimit:          int32(filter.Limit),
+		})
+	}
 	if err != nil {
-		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to get submissions by assignment_id")
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions by assignment_id")
 	}
 
 	return toEntitySlice(pgSubs), nil
@@ -105,6 +184,134 @@ func (r *postgresRepository) GetByAuthorID(ctx context.Context, authorID string)
 	return toEntitySlice(pgSubs), nil
 }
 
+func (r *postgresRepository) GetUsage(ctx context.Context, authorID, assignmentID string) (*entity.Usage, error) {
+	row, err := r.queries.GetAuthorUsage(ctx, GetAuthorUsageParams{
+		AuthorID:     authorID,
+		AssignmentID: assignmentID,
+	})
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to get author usage")
+	}
+
+	return &entity.Usage{
+		AssignmentSubmissions: row.AssignmentSubmissions,
+		BytesUsed:             row.BytesUsed,
+	}, nil
+}
+
+func (r *postgresRepository) SoftDelete(ctx context.Context, submissionID uuid.UUID, assignmentID string) (*entity.Submission, error) {
+	submission, tx, err := r.SoftDeleteWithTx(ctx, submissionID, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to commit delete tx")
+	}
+
+	return submission, nil
+}
+
+func (r *postgresRepository) SoftDeleteWithTx(ctx context.Context, submissionID uuid.UUID, assignmentID string) (*entity.Submission, repository.Transaction, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to begin delete tx")
+	}
+
+	pgSub, err := r.queries.WithTx(tx).SoftDeleteSubmission(ctx, SoftDeleteSubmissionParams{
+		SubmissionID: submissionID,
+		AssignmentID: assignmentID,
+	})
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		if stdErrors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, apperr.Wrap(err, apperr.CodeNotFound, "submission not found")
+		}
+		return nil, nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to delete submission")
+	}
+
+	return toEntity(pgSub), &pgxTxWrapper{tx: tx}, nil
+}
+
+func (r *postgresRepository) ListAfterID(ctx context.Context, after uuid.UUID, limit int) ([]*entity.Submission, error) {
+	pgSubs, err := r.queries.ListSubmissionsAfterID(ctx, ListSubmissionsAfterIDParams{
+		SubmissionID: after,
+		PageLimit:    int32(limit),
+	})
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions")
+	}
+
+	return toEntitySlice(pgSubs), nil
+}
+
+func (r *postgresRepository) SetStatus(ctx context.Context, submissionID uuid.UUID, status entity.SubmissionStatus) error {
+	err := r.queries.UpdateSubmissionStatus(ctx, UpdateSubmissionStatusParams{
+		SubmissionID: submissionID,
+		Status:       string(status),
+	})
+	if err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to update submission status")
+	}
+	return nil
+}
+
+func (r *postgresRepository) ListAllByAuthorID(ctx context.Context, authorID string) ([]*entity.Submission, error) {
+	pgSubs, err := r.queries.GetAllSubmissionsByAuthorID(ctx, authorID)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions by author_id")
+	}
+
+	return toEntitySlice(pgSubs), nil
+}
+
+func (r *postgresRepository) PurgeByAuthorID(ctx context.Context, authorID string, audit *entity.PurgeAudit) (*entity.PurgeAudit, error) {
+	submissionIDs, err := json.Marshal(audit.SubmissionIDs)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "failed to encode purged submission ids")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to begin purge tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := r.queries.WithTx(tx)
+	if _, err := queries.DeleteSubmissionsByAuthorID(ctx, DeleteSubmissionsByAuthorIDParams{
+		AuthorID:      authorID,
+		SubmissionIds: audit.SubmissionIDs,
+	}); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to delete submissions by author_id")
+	}
+
+	pgAudit, err := queries.CreatePurgeAudit(ctx, CreatePurgeAuditParams{
+		AuthorHash:      audit.AuthorHash,
+		SubmissionIds:   string(submissionIDs),
+		ObjectsDeleted:  int32(audit.ObjectsDeleted),
+		ReportsScrubbed: audit.ReportsScrubbed,
+		RequestedBy:     audit.RequestedBy,
+	})
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to write purge audit")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to commit purge tx")
+	}
+
+	return &entity.PurgeAudit{
+		AuditID:         pgAudit.AuditID,
+		AuthorHash:      pgAudit.AuthorHash,
+		SubmissionIDs:   audit.SubmissionIDs,
+		ObjectsDeleted:  int(pgAudit.ObjectsDeleted),
+		ReportsScrubbed: pgAudit.ReportsScrubbed,
+		RequestedBy:     pgAudit.RequestedBy,
+		CreatedAt:       pgAudit.CreatedAt,
+	}, nil
+}
+
 type pgxTxWrapper struct {
 	tx pgx.Tx
 }
@@ -6,15 +6,36 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvent, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error
+	CreatePurgeAudit(ctx context.Context, arg CreatePurgeAuditParams) (PurgeAudit, error)
 	CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (Submission, error)
+	CreateSubmissionAt(ctx context.Context, arg CreateSubmissionAtParams) (Submission, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
+	DeleteOutboxEvent(ctx context.Context, id int64) error
+	DeleteSubmissionsByAuthorID(ctx context.Context, arg DeleteSubmissionsByAuthorIDParams) (int64, error)
+	GetAllSubmissionsByAuthorID(ctx context.Context, authorID string) ([]Submission, error)
+	GetAuthorUsage(ctx context.Context, arg GetAuthorUsageParams) (GetAuthorUsageRow, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
 	GetSubmissionByID(ctx context.Context, submissionID uuid.UUID) (Submission, error)
-	GetSubmissionsByAssignmentID(ctx context.Context, assignmentID string) ([]Submission, error)
 	GetSubmissionsByAuthorID(ctx context.Context, authorID string) ([]Submission, error)
+	ListSubmissionsAfterID(ctx context.Context, arg ListSubmissionsAfterIDParams) ([]Submission, error)
+	ListSubmissionsByAssignmentIDAsc(ctx context.Context, arg ListSubmissionsByAssignmentIDAscParams) ([]Submission, error)
+	ListSubmissionsByAssignmentIDDesc(ctx context.Context, arg ListSubmissionsByAssignmentIDDescParams) ([]Submission, error)
+	ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error
+	ReleaseOutboxEvents(ctx context.Context, arg ReleaseOutboxEventsParams) (int64, error)
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error)
+	RetryOutboxEvent(ctx context.Context, arg RetryOutboxEventParams) error
+	SoftDeleteSubmission(ctx context.Context, arg SoftDeleteSubmissionParams) (Submission, error)
+	UpdateSubmissionFileInfo(ctx context.Context, arg UpdateSubmissionFileInfoParams) error
+	UpdateSubmissionStatus(ctx context.Context, arg UpdateSubmissionStatusParams) error
 }
 
 var _ Querier = (*Queries)(nil)
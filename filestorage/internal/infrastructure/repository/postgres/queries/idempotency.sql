@@ -0,0 +1,21 @@
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $3, response_body = $4
+WHERE scope = $1 AND idempotency_key = $2;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < sqlc.arg(before);
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE scope = $1 AND idempotency_key = $2;
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE scope = $1 AND idempotency_key = $2 AND status_code = 0;
+
+-- name: ReserveIdempotencyKey :execrows
+INSERT INTO idempotency_keys (scope, idempotency_key, fingerprint, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (scope, idempotency_key) DO NOTHING;
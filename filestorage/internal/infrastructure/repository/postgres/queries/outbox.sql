@@ -0,0 +1,29 @@
+-- name: ClaimOutboxEvents :many
+UPDATE outbox_events
+SET available_at = sqlc.arg(lease_until), attempts = attempts + 1
+WHERE id IN (
+    SELECT id FROM outbox_events
+    WHERE available_at <= sqlc.arg(now)
+    ORDER BY id
+    LIMIT sqlc.arg(page_limit)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox_events (event_type, assignment_id, submission_id, available_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteOutboxEvent :exec
+DELETE FROM outbox_events
+WHERE id = $1;
+
+-- name: ReleaseOutboxEvents :execrows
+UPDATE outbox_events
+SET available_at = $2
+WHERE assignment_id = $1 AND available_at IS NULL;
+
+-- name: RetryOutboxEvent :exec
+UPDATE outbox_events
+SET available_at = $2, last_error = $3
+WHERE id = $1;
@@ -0,0 +1,4 @@
+-- name: CreatePurgeAudit :one
+INSERT INTO purge_audit (author_hash, submission_ids, objects_deleted, reports_scrubbed, requested_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
@@ -3,17 +3,76 @@ INSERT INTO submissions (assignment_id, author_id)
 VALUES ($1, $2)
 RETURNING *;
 
--- name: GetSubmissionByID :one
+-- name: CreateSubmissionAt :one
+INSERT INTO submissions (assignment_id, author_id, created_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: DeleteSubmissionsByAuthorID :execrows
+DELETE FROM submissions
+WHERE author_id = $1 AND submission_id = ANY(@submission_ids::uuid[]);
+
+-- name: GetAllSubmissionsByAuthorID :many
 SELECT * FROM submissions
-WHERE submission_id = $1;
+WHERE author_id = $1
+ORDER BY created_at ASC, submission_id ASC;
 
--- name: GetSubmissionsByAssignmentID :many
+-- name: GetAuthorUsage :one
+SELECT
+    COUNT(*) FILTER (WHERE assignment_id = $2)::bigint AS assignment_submissions,
+    COALESCE(SUM(size_bytes), 0)::bigint AS bytes_used
+FROM submissions
+WHERE author_id = $1 AND deleted_at IS NULL;
+
+-- name: GetSubmissionByID :one
 SELECT * FROM submissions
-WHERE assignment_id = $1
-ORDER BY created_at DESC;
+WHERE submission_id = $1 AND deleted_at IS NULL;
 
 -- name: GetSubmissionsByAuthorID :many
 SELECT * FROM submissions
-WHERE author_id = $1
+WHERE author_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
+-- name: ListSubmissionsByAssignmentIDDesc :many
+SELECT * FROM submissions
+WHERE assignment_id = @assignment_id
+  AND deleted_at IS NULL
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR created_at >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR created_at < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+       OR (created_at, submission_id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_submission_id)::uuid))
+ORDER BY created_at DESC, submission_id DESC
+LIMIT @page_limit;
+
+-- name: ListSubmissionsByAssignmentIDAsc :many
+SELECT * FROM submissions
+WHERE assignment_id = @assignment_id
+  AND deleted_at IS NULL
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR created_at >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR created_at < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(cursor_created_at)::timestamp IS NULL
+       OR (created_at, submission_id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_submission_id)::uuid))
+ORDER BY created_at ASC, submission_id ASC
+LIMIT @page_limit;
+
+-- name: ListSubmissionsAfterID :many
+SELECT * FROM submissions
+WHERE submission_id > @submission_id
+ORDER BY submission_id
+LIMIT @page_limit;
+
+-- name: SoftDeleteSubmission :one
+UPDATE submissions
+SET deleted_at = COALESCE(deleted_at, NOW())
+WHERE submission_id = @submission_id AND (@assignment_id::text = '' OR assignment_id = @assignment_id::text)
+RETURNING *;
+
+-- name: UpdateSubmissionStatus :exec
+UPDATE submissions
+SET status = $2
+WHERE submission_id = $1;
+
+-- name: UpdateSubmissionFileInfo :exec
+UPDATE submissions
+SET size_bytes = $2, checksum = $3, content_type = $4, status = $5, original_filename = $6
+WHERE submission_id = $1;
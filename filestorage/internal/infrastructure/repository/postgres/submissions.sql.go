@@ -7,6 +7,7 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,7 +15,7 @@ import (
 const createSubmission = `-- name: CreateSubmission :one
 INSERT INTO submissions (assignment_id, author_id)
 VALUES ($1, $2)
-RETURNING submission_id, assignment_id, author_id, created_at
+RETURNING submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename
 `
 
 type CreateSubmissionParams struct {
@@ -30,35 +31,72 @@ func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionPara
 		&i.AssignmentID,
 		&i.AuthorID,
 		&i.CreatedAt,
+		&i.SizeBytes,
+		&i.Checksum,
+		&i.DeletedAt,
+		&i.Status,
+		&i.ContentType,
+		&i.OriginalFilename,
 	)
 	return i, err
 }
 
-const getSubmissionByID = `-- name: GetSubmissionByID :one
-SELECT submission_id, assignment_id, author_id, created_at FROM submissions
-WHERE submission_id = $1
+const createSubmissionAt = `-- name: CreateSubmissionAt :one
+INSERT INTO submissions (assignment_id, author_id, created_at)
+VALUES ($1, $2, $3)
+RETURNING submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename
 `
 
-func (q *Queries) GetSubmissionByID(ctx context.Context, submissionID uuid.UUID) (Submission, error) {
-	row := q.db.QueryRow(ctx, getSubmissionByID, submissionID)
+type CreateSubmissionAtParams struct {
+	AssignmentID string    `json:"assignment_id"`
+	AuthorID     string    `json:"author_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateSubmissionAt(ctx context.Context, arg CreateSubmissionAtParams) (Submission, error) {
+	row := q.db.QueryRow(ctx, createSubmissionAt, arg.AssignmentID, arg.AuthorID, arg.CreatedAt)
 	var i Submission
 	err := row.Scan(
 		&i.SubmissionID,
 		&i.AssignmentID,
 		&i.AuthorID,
 		&i.CreatedAt,
+		&i.SizeBytes,
+		&i.Checksum,
+		&i.DeletedAt,
+		&i.Status,
+		&i.ContentType,
+		&i.OriginalFilename,
 	)
 	return i, err
 }
 
-const getSubmissionsByAssignmentID = `-- name: GetSubmissionsByAssignmentID :many
-SELECT submission_id, assignment_id, author_id, created_at FROM submissions
-WHERE assignment_id = $1
-ORDER BY created_at DESC
+const deleteSubmissionsByAuthorID = `-- name: DeleteSubmissionsByAuthorID :execrows
+DELETE FROM submissions
+WHERE author_id = $1 AND submission_id = ANY($2::uuid[])
+`
+
+type DeleteSubmissionsByAuthorIDParams struct {
+	AuthorID      string      `json:"author_id"`
+	SubmissionIds []uuid.UUID `json:"submission_ids"`
+}
+
+func (q *Queries) DeleteSubmissionsByAuthorID(ctx context.Context, arg DeleteSubmissionsByAuthorIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSubmissionsByAuthorID, arg.AuthorID, arg.SubmissionIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getAllSubmissionsByAuthorID = `-- name: GetAllSubmissionsByAuthorID :many
+SELECT submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename FROM submissions
+WHERE author_id = $1
+ORDER BY created_at ASC, submission_id ASC
 `
 
-func (q *Queries) GetSubmissionsByAssignmentID(ctx context.Context, assignmentID string) ([]Submission, error) {
-	rows, err := q.db.Query(ctx, getSubmissionsByAssignmentID, assignmentID)
+func (q *Queries) GetAllSubmissionsByAuthorID(ctx context.Context, authorID string) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, getAllSubmissionsByAuthorID, authorID)
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +109,12 @@ func (q *Queries) GetSubmissionsByAssignmentID(ctx context.Context, assignmentID
 			&i.AssignmentID,
 			&i.AuthorID,
 			&i.CreatedAt,
+			&i.SizeBytes,
+			&i.Checksum,
+			&i.DeletedAt,
+			&i.Status,
+			&i.ContentType,
+			&i.OriginalFilename,
 		); err != nil {
 			return nil, err
 		}
@@ -82,9 +126,60 @@ func (q *Queries) GetSubmissionsByAssignmentID(ctx context.Context, assignmentID
 	return items, nil
 }
 
+const getAuthorUsage = `-- name: GetAuthorUsage :one
+SELECT
+    COUNT(*) FILTER (WHERE assignment_id = $2)::bigint AS assignment_submissions,
+    COALESCE(SUM(size_bytes), 0)::bigint AS bytes_used
+FROM submissions
+WHERE author_id = $1 AND deleted_at IS NULL
+`
+
+type GetAuthorUsageParams struct {
+	AuthorID     string `json:"author_id"`
+	AssignmentID string `json:"assignment_id"`
+}
+
+type GetAuthorUsageRow struct {
+	AssignmentSubmissions int64 `json:"assignment_submissions"`
+	BytesUsed             int64 `json:"bytes_used"`
+}
+
+func (q *Queries) GetAuthorUsage(ctx context.Context, arg GetAuthorUsageParams) (GetAuthorUsageRow, error) {
+	row := q.db.QueryRow(ctx, getAuthorUsage, arg.AuthorID, arg.AssignmentID)
+	var i GetAuthorUsageRow
+	err := row.Scan(
+		&i.AssignmentSubmissions,
+		&i.BytesUsed,
+	)
+	return i, err
+}
+
+const getSubmissionByID = `-- name: GetSubmissionByID :one
+SELECT submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename FROM submissions
+WHERE submission_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetSubmissionByID(ctx context.Context, submissionID uuid.UUID) (Submission, error) {
+	row := q.db.QueryRow(ctx, getSubmissionByID, submissionID)
+	var i Submission
+	err := row.Scan(
+		&i.SubmissionID,
+		&i.AssignmentID,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.SizeBytes,
+		&i.Checksum,
+		&i.DeletedAt,
+		&i.Status,
+		&i.ContentType,
+		&i.OriginalFilename,
+	)
+	return i, err
+}
+
 const getSubmissionsByAuthorID = `-- name: GetSubmissionsByAuthorID :many
-SELECT submission_id, assignment_id, author_id, created_at FROM submissions
-WHERE author_id = $1
+SELECT submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename FROM submissions
+WHERE author_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -102,6 +197,173 @@ func (q *Queries) GetSubmissionsByAuthorID(ctx context.Context, authorID string)
 			&i.AssignmentID,
 			&i.AuthorID,
 			&i.CreatedAt,
+			&i.SizeBytes,
+			&i.Checksum,
+			&i.DeletedAt,
+			&i.Status,
+			&i.ContentType,
+			&i.OriginalFilename,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmissionsByAssignmentIDDesc = `-- name: ListSubmissionsByAssignmentIDDesc :many
+SELECT submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename FROM submissions
+WHERE assignment_id = $1
+  AND deleted_at IS NULL
+  AND ($2::timestamp IS NULL OR created_at >= $2::timestamp)
+  AND ($3::timestamp IS NULL OR created_at < $3::timestamp)
+  AND ($4::timestamp IS NULL
+       OR (created_at, submission_id) < ($4::timestamp, $5::uuid))
+ORDER BY created_at DESC, submission_id DESC
+LIMIT $6
+`
+
+type ListSubmissionsByAssignmentIDDescParams struct {
+	AssignmentID       string        `json:"assignment_id"`
+	CreatedAfter       *time.Time    `json:"created_after"`
+	CreatedBefore      *time.Time    `json:"created_before"`
+	CursorCreatedAt    *time.Time    `json:"cursor_created_at"`
+	CursorSubmissionID uuid.NullUUID `json:"cursor_submission_id"`
+	PageLimit          int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListSubmissionsByAssignmentIDDesc(ctx context.Context, arg ListSubmissionsByAssignmentIDDescParams) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listSubmissionsByAssignmentIDDesc,
+		arg.AssignmentID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.CursorSubmissionID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Submission
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.AssignmentID,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.SizeBytes,
+			&i.Checksum,
+			&i.DeletedAt,
+			&i.Status,
+			&i.ContentType,
+			&i.OriginalFilename,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmissionsByAssignmentIDAsc = `-- name: ListSubmissionsByAssignmentIDAsc :many
+SELECT submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename FROM submissions
+WHERE assignment_id = $1
+  AND deleted_at IS NULL
+  AND ($2::timestamp IS NULL OR created_at >= $2::timestamp)
+  AND ($3::timestamp IS NULL OR created_at < $3::timestamp)
+  AND ($4::timestamp IS NULL
+       OR (created_at, submission_id) > ($4::timestamp, $5::uuid))
+ORDER BY created_at ASC, submission_id ASC
+LIMIT $6
+`
+
+type ListSubmissionsByAssignmentIDAscParams struct {
+	AssignmentID       string        `json:"assignment_id"`
+	CreatedAfter       *time.Time    `json:"created_after"`
+	CreatedBefore      *time.Time    `json:"created_before"`
+	CursorCreatedAt    *time.Time    `json:"cursor_created_at"`
+	CursorSubmissionID uuid.NullUUID `json:"cursor_submission_id"`
+	PageLimit          int32         `json:"page_limit"`
+}
+
+func (q *Queries) ListSubmissionsByAssignmentIDAsc(ctx context.Context, arg ListSubmissionsByAssignmentIDAscParams) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listSubmissionsByAssignmentIDAsc,
+		arg.AssignmentID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.CursorSubmissionID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Submission
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.AssignmentID,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.SizeBytes,
+			&i.Checksum,
+			&i.DeletedAt,
+			&i.Status,
+			&i.ContentType,
+			&i.OriginalFilename,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmissionsAfterID = `-- name: ListSubmissionsAfterID :many
+SELECT submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename FROM submissions
+WHERE submission_id > $1
+ORDER BY submission_id
+LIMIT $2
+`
+
+type ListSubmissionsAfterIDParams struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	PageLimit    int32     `json:"page_limit"`
+}
+
+func (q *Queries) ListSubmissionsAfterID(ctx context.Context, arg ListSubmissionsAfterIDParams) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listSubmissionsAfterID, arg.SubmissionID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Submission
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.AssignmentID,
+			&i.AuthorID,
+			&i.CreatedAt,
+			&i.SizeBytes,
+			&i.Checksum,
+			&i.DeletedAt,
+			&i.Status,
+			&i.ContentType,
+			&i.OriginalFilename,
 		); err != nil {
 			return nil, err
 		}
@@ -112,3 +374,76 @@ func (q *Queries) GetSubmissionsByAuthorID(ctx context.Context, authorID string)
 	}
 	return items, nil
 }
+
+const softDeleteSubmission = `-- name: SoftDeleteSubmission :one
+UPDATE submissions
+SET deleted_at = COALESCE(deleted_at, NOW())
+WHERE submission_id = $1 AND ($2::text = '' OR assignment_id = $2::text)
+RETURNING submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename
+`
+
+type SoftDeleteSubmissionParams struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	AssignmentID string    `json:"assignment_id"`
+}
+
+func (q *Queries) SoftDeleteSubmission(ctx context.Context, arg SoftDeleteSubmissionParams) (Submission, error) {
+	row := q.db.QueryRow(ctx, softDeleteSubmission, arg.SubmissionID, arg.AssignmentID)
+	var i Submission
+	err := row.Scan(
+		&i.SubmissionID,
+		&i.AssignmentID,
+		&i.AuthorID,
+		&i.CreatedAt,
+		&i.SizeBytes,
+		&i.Checksum,
+		&i.DeletedAt,
+		&i.Status,
+		&i.ContentType,
+		&i.OriginalFilename,
+	)
+	return i, err
+}
+
+const updateSubmissionStatus = `-- name: UpdateSubmissionStatus :exec
+UPDATE submissions
+SET status = $2
+WHERE submission_id = $1
+`
+
+type UpdateSubmissionStatusParams struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	Status       string    `json:"status"`
+}
+
+func (q *Queries) UpdateSubmissionStatus(ctx context.Context, arg UpdateSubmissionStatusParams) error {
+	_, err := q.db.Exec(ctx, updateSubmissionStatus, arg.SubmissionID, arg.Status)
+	return err
+}
+
+const updateSubmissionFileInfo = `-- name: UpdateSubmissionFileInfo :exec
+UPDATE submissions
+SET size_bytes = $2, checksum = $3, content_type = $4, status = $5, original_filename = $6
+WHERE submission_id = $1
+`
+
+type UpdateSubmissionFileInfoParams struct {
+	SubmissionID     uuid.UUID `json:"submission_id"`
+	SizeBytes        int64     `json:"size_bytes"`
+	Checksum         string    `json:"checksum"`
+	ContentType      string    `json:"content_type"`
+	Status           string    `json:"status"`
+	OriginalFilename string    `json:"original_filename"`
+}
+
+func (q *Queries) UpdateSubmissionFileInfo(ctx context.Context, arg UpdateSubmissionFileInfoParams) error {
+	_, err := q.db.Exec(ctx, updateSubmissionFileInfo,
+		arg.SubmissionID,
+		arg.SizeBytes,
+		arg.Checksum,
+		arg.ContentType,
+		arg.Status,
+		arg.OriginalFilename,
+	)
+	return err
+}
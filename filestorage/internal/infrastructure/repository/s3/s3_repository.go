@@ -1,10 +1,12 @@
 package s3
 
 import (
-	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"time"
 
 	apperr "filestorage/internal/common/errors"
 	"filestorage/internal/domain/repository"
@@ -12,15 +14,21 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
 )
 
 type s3Repository struct {
-	client *s3.Client
-	bucket string
+	client    *s3.Client
+	uploader  *manager.Uploader
+	presigner *s3.PresignClient
+	bucket    string
 }
 
-func NewS3Repository(ctx context.Context, bucket, endpoint, region string) (repository.S3Repository, error) {
+// NewS3Repository connects to the bucket at endpoint. Presigned URLs are
+// signed for publicEndpoint instead, since the signature covers the host.
+func NewS3Repository(ctx context.Context, bucket, endpoint, publicEndpoint, region string) (repository.S3Repository, error) {
 	opts := []func(*config.LoadOptions) error{
 		config.WithRegion(region),
 	}
@@ -57,17 +65,29 @@ func NewS3Repository(ctx context.Context, bucket, endpoint, region string) (repo
 		}
 	})
 
+	presignClient := client
+	if publicEndpoint != "" && publicEndpoint != endpoint {
+		presignClient = s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.BaseEndpoint = aws.String(publicEndpoint)
+			o.UsePathStyle = true
+		})
+	}
+
 	return &s3Repository{
-		client: client,
-		bucket: bucket,
+		client:    client,
+		uploader:  manager.NewUploader(client),
+		presigner: s3.NewPresignClient(presignClient),
+		bucket:    bucket,
 	}, nil
 }
 
-func (r *s3Repository) UploadFile(ctx context.Context, key string, data []byte, contentType string) error {
-	_, err := r.client.PutObject(ctx, &s3.PutObjectInput{
+func (r *s3Repository) UploadFile(ctx context.Context, key string, body io.Reader, contentType string) error {
+	// The uploader buffers at most a few parts at a time and switches to a
+	// multipart upload once the body outgrows a single part.
+	_, err := r.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(r.bucket),
 		Key:         aws.String(key),
-		Body:        bytes.NewReader(data),
+		Body:        body,
 		ContentType: aws.String(contentType),
 	})
 
@@ -84,12 +104,37 @@ func (r *s3Repository) GetFile(ctx context.Context, key string) (io.ReadCloser,
 	})
 
 	if err != nil {
+		if isNoSuchKey(err) {
+			return nil, apperr.Wrap(err, apperr.CodeNotFound, "object not found")
+		}
 		return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to get object")
 	}
 
 	return result.Body, nil
 }
 
+func (r *s3Repository) GetFileRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	byteRange := fmt.Sprintf("bytes=%d-", offset)
+	if length >= 0 {
+		byteRange = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	}
+
+	result, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(byteRange),
+	})
+
+	if err != nil {
+		if isNoSuchKey(err) {
+			return nil, apperr.Wrap(err, apperr.CodeNotFound, "object not found")
+		}
+		return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to get object range")
+	}
+
+	return result.Body, nil
+}
+
 func (r *s3Repository) DeleteFile(ctx context.Context, key string) error {
 	_, err := r.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(r.bucket),
@@ -101,3 +146,44 @@ func (r *s3Repository) DeleteFile(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+func (r *s3Repository) ListObjects(ctx context.Context) ([]repository.ObjectInfo, error) {
+	var objects []repository.ObjectInfo
+
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(r.bucket),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, apperr.Wrap(err, apperr.CodeStorage, "failed to list objects")
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, repository.ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+func (r *s3Repository) PresignGetFile(ctx context.Context, key, filename string, ttl time.Duration) (string, error) {
+	req, err := r.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(r.bucket),
+		Key:                        aws.String(key),
+		ResponseContentType:        aws.String("application/octet-stream"),
+		ResponseContentDisposition: aws.String(fmt.Sprintf(`attachment; filename="%s"`, filename)),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", apperr.Wrap(err, apperr.CodeStorage, "failed to presign object url")
+	}
+	return req.URL, nil
+}
+
+func isNoSuchKey(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey"
+}
@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+)
+
+const (
+	reserveIdempotencyKey = `INSERT INTO idempotency_keys (scope, idempotency_key, fingerprint, created_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (scope, idempotency_key) DO NOTHING`
+
+	getIdempotencyKey = `SELECT scope, idempotency_key, fingerprint, status_code, response_body, created_at
+FROM idempotency_keys
+WHERE scope = ? AND idempotency_key = ?`
+
+	completeIdempotencyKey = `UPDATE idempotency_keys
+SET status_code = ?3, response_body = ?4
+WHERE scope = ?1 AND idempotency_key = ?2`
+
+	releaseIdempotencyKey = `DELETE FROM idempotency_keys
+WHERE scope = ? AND idempotency_key = ? AND status_code = 0`
+
+	deleteExpiredIdempotencyKeys = `DELETE FROM idempotency_keys
+WHERE created_at < ?`
+)
+
+type sqliteIdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteIdempotencyRepository(db *sql.DB) repository.IdempotencyRepository {
+	return &sqliteIdempotencyRepository{db: db}
+}
+
+func (r *sqliteIdempotencyRepository) Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error) {
+	// The key found taken may be released before it is read; the insert is
+	// then tried once more.
+	for range 2 {
+		res, err := r.db.ExecContext(ctx, reserveIdempotencyKey, key.Scope, key.Key, key.Fingerprint, formatTime(&key.CreatedAt))
+		if err != nil {
+			return nil, false, apperr.Wrap(err, apperr.CodeDatabase, "failed to reserve idempotency key")
+		}
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return nil, false, apperr.Wrap(err, apperr.CodeDatabase, "failed to reserve idempotency key")
+		}
+		if inserted == 1 {
+			return key, true, nil
+		}
+
+		var (
+			stored    entity.IdempotencyKey
+			createdAt string
+		)
+		err = r.db.QueryRowContext(ctx, getIdempotencyKey, key.Scope, key.Key).
+			Scan(&stored.Scope, &stored.Key, &stored.Fingerprint, &stored.StatusCode, &stored.ResponseBody, &createdAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, false, apperr.Wrap(err, apperr.CodeDatabase, "failed to load idempotency key")
+		}
+		stored.CreatedAt, err = time.Parse(timeLayout, createdAt)
+		if err != nil {
+			return nil, false, apperr.Wrap(fmt.Errorf("invalid created_at %q: %w", createdAt, err), apperr.CodeDatabase, "failed to load idempotency key")
+		}
+		return &stored, false, nil
+	}
+	return nil, false, apperr.New(apperr.CodeDatabase, "failed to reserve idempotency key")
+}
+
+func (r *sqliteIdempotencyRepository) Complete(ctx context.Context, scope, key string, statusCode int, body []byte) error {
+	if _, err := r.db.ExecContext(ctx, completeIdempotencyKey, scope, key, statusCode, body); err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to complete idempotency key")
+	}
+	return nil
+}
+
+func (r *sqliteIdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	if _, err := r.db.ExecContext(ctx, releaseIdempotencyKey, scope, key); err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to release idempotency key")
+	}
+	return nil
+}
+
+func (r *sqliteIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, deleteExpiredIdempotencyKeys, formatTime(&before))
+	if err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeDatabase, "failed to delete expired idempotency keys")
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeDatabase, "failed to delete expired idempotency keys")
+	}
+	return deleted, nil
+}
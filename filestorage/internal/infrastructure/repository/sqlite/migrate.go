@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"filestorage/migrations"
+)
+
+// Migrator applies the embedded SQLite migrations. Every run holds the
+// database write lock (BEGIN IMMEDIATE) from reading schema_migrations to
+// the last migration, so concurrent starts apply each version once.
+type Migrator struct {
+	db *sql.DB
+}
+
+func NewMigrator(db *sql.DB) *Migrator {
+	return &Migrator{db: db}
+}
+
+// Up applies all pending migrations and returns them.
+func (m *Migrator) Up(ctx context.Context) ([]migrations.Migration, error) {
+	var done []migrations.Migration
+	err := m.locked(ctx, func(conn *sql.Conn, all []migrations.Migration, applied map[int64]migrations.Applied) error {
+		for _, mig := range migrations.Pending(all, applied) {
+			if _, err := conn.ExecContext(ctx, mig.Up); err != nil {
+				return fmt.Errorf("apply migration %s: %w", mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+				mig.Version, mig.Name, time.Now().UTC().Format(timeLayout),
+			); err != nil {
+				return fmt.Errorf("record migration %s: %w", mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]migrations.Migration, error) {
+	var done []migrations.Migration
+	err := m.locked(ctx, func(conn *sql.Conn, all []migrations.Migration, applied map[int64]migrations.Applied) error {
+		rollback, err := migrations.Rollback(all, applied, steps)
+		if err != nil {
+			return err
+		}
+		for _, mig := range rollback {
+			if _, err := conn.ExecContext(ctx, mig.Down); err != nil {
+				return fmt.Errorf("revert migration %s: %w", mig.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, mig.Version); err != nil {
+				return fmt.Errorf("record migration %s: %w", mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]migrations.Status, error) {
+	var result []migrations.Status
+	err := m.locked(ctx, func(_ *sql.Conn, all []migrations.Migration, applied map[int64]migrations.Applied) error {
+		result = migrations.Statuses(all, applied)
+		return nil
+	})
+	return result, err
+}
+
+// locked runs fn in one write transaction; SQLite DDL is transactional, so
+// a failed migration leaves the schema as it was.
+func (m *Migrator) locked(ctx context.Context, fn func(*sql.Conn, []migrations.Migration, map[int64]migrations.Applied) error) error {
+	all, err := migrations.Load(migrations.SQLite, "sqlite")
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+		return fmt.Errorf("lock database: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_, _ = conn.ExecContext(context.Background(), `ROLLBACK`)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TEXT NOT NULL
+)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	applied, err := readApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if err := fn(conn, all, applied); err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, `COMMIT`); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func readApplied(ctx context.Context, conn *sql.Conn) (map[int64]migrations.Applied, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]migrations.Applied)
+	for rows.Next() {
+		var (
+			version   int64
+			name      string
+			appliedAt string
+		)
+		if err := rows.Scan(&version, &name, &appliedAt); err != nil {
+			return nil, fmt.Errorf("read schema_migrations: %w", err)
+		}
+		parsed, err := time.Parse(timeLayout, appliedAt)
+		if err != nil {
+			return nil, fmt.Errorf("read schema_migrations: %w", err)
+		}
+		applied[version] = migrations.Applied{Name: name, AppliedAt: parsed}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	return applied, nil
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const outboxColumns = `id, event_type, assignment_id, submission_id, created_at, available_at, attempts, last_error`
+
+const (
+	insertOutboxEvent = `INSERT INTO outbox_events (event_type, assignment_id, submission_id, created_at, available_at)
+VALUES (?, ?, ?, ?, ?)`
+
+	// SQLite has a single writer, so the update needs no row locking.
+	claimOutboxEvents = `UPDATE outbox_events
+SET available_at = ?1, attempts = attempts + 1
+WHERE id IN (
+    SELECT id FROM outbox_events
+    WHERE available_at <= ?2
+    ORDER BY id
+    LIMIT ?3
+)
+RETURNING ` + outboxColumns
+
+	deleteOutboxEvent = `DELETE FROM outbox_events
+WHERE id = ?`
+
+	retryOutboxEvent = `UPDATE outbox_events
+SET available_at = ?2, last_error = ?3
+WHERE id = ?1`
+
+	releaseOutboxEvents = `UPDATE outbox_events
+SET available_at = ?2
+WHERE assignment_id = ?1 AND available_at IS NULL`
+)
+
+type sqliteOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &sqliteOutboxRepository{db: db}
+}
+
+func scanOutboxEvent(row rowScanner) (*entity.OutboxEvent, error) {
+	var (
+		submissionID string
+		eventType    string
+		createdAt    string
+		availableAt  sql.NullString
+		event        entity.OutboxEvent
+	)
+	if err := row.Scan(&event.ID, &eventType, &event.AssignmentID, &submissionID, &createdAt, &availableAt, &event.Attempts, &event.LastError); err != nil {
+		return nil, err
+	}
+
+	parsedID, err := uuid.Parse(submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid submission_id %q: %w", submissionID, err)
+	}
+	parsedCreatedAt, err := time.Parse(timeLayout, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created_at %q: %w", createdAt, err)
+	}
+	if availableAt.Valid {
+		parsedAvailableAt, err := time.Parse(timeLayout, availableAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid available_at %q: %w", availableAt.String, err)
+		}
+		event.AvailableAt = &parsedAvailableAt
+	}
+
+	event.Type = entity.OutboxEventType(eventType)
+	event.SubmissionID = parsedID
+	event.CreatedAt = parsedCreatedAt
+	return &event, nil
+}
+
+func (r *sqliteOutboxRepository) AddWithTx(_ context.Context, tx repository.Transaction, event *entity.OutboxEvent) error {
+	deferred, ok := tx.(*deferredTx)
+	if !ok {
+		return apperr.New(apperr.CodeDatabase, "unsupported transaction type")
+	}
+
+	createdAt := time.Now()
+	deferred.add(insertOutboxEvent, "failed to store outbox event",
+		string(event.Type), event.AssignmentID, event.SubmissionID.String(), formatTime(&createdAt), formatTime(event.AvailableAt),
+	)
+	return nil
+}
+
+func (r *sqliteOutboxRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx, claimOutboxEvents, formatTime(&leaseUntil), formatTime(&now), limit)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to claim outbox events")
+	}
+	defer rows.Close()
+
+	events := make([]*entity.OutboxEvent, 0)
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to claim outbox events")
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to claim outbox events")
+	}
+	return events, nil
+}
+
+func (r *sqliteOutboxRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := r.db.ExecContext(ctx, deleteOutboxEvent, id); err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to delete outbox event")
+	}
+	return nil
+}
+
+func (r *sqliteOutboxRepository) Retry(ctx context.Context, id int64, availableAt time.Time, lastError string) error {
+	if _, err := r.db.ExecContext(ctx, retryOutboxEvent, id, formatTime(&availableAt), lastError); err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to reschedule outbox event")
+	}
+	return nil
+}
+
+func (r *sqliteOutboxRepository) Release(ctx context.Context, assignmentID string, availableAt time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, releaseOutboxEvents, assignmentID, formatTime(&availableAt))
+	if err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeDatabase, "failed to release outbox events")
+	}
+	released, err := res.RowsAffected()
+	if err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeDatabase, "failed to release outbox events")
+	}
+	return released, nil
+}
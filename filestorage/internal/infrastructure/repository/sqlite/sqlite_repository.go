@@ -0,0 +1,468 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	apperr "filestorage/internal/common/errors"
+	"filestorage/internal/domain/entity"
+	"filestorage/internal/domain/repository"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// timeLayout is fixed-width so that lexical order of the stored text matches
+// chronological order; precision follows Postgres' timestamp.
+const timeLayout = "2006-01-02 15:04:05.000000"
+
+const submissionColumns = `submission_id, assignment_id, author_id, created_at, size_bytes, checksum, deleted_at, status, content_type, original_filename`
+
+const (
+	insertSubmission = `INSERT INTO submissions (submission_id, assignment_id, author_id, created_at)
+VALUES (?, ?, ?, ?)`
+
+	updateSubmissionFileInfo = `UPDATE submissions
+SET size_bytes = ?2, checksum = ?3, content_type = ?4, status = ?5, original_filename = ?6
+WHERE submission_id = ?1`
+
+	softDeleteSubmission = `UPDATE submissions
+SET deleted_at = COALESCE(deleted_at, ?3)
+WHERE submission_id = ?1 AND (?2 = '' OR assignment_id = ?2)
+RETURNING ` + submissionColumns
+
+	getAllSubmissionsByAuthorID = `SELECT ` + submissionColumns + ` FROM submissions
+WHERE author_id = ?
+ORDER BY created_at ASC, submission_id ASC`
+
+	deleteSubmissionsByAuthorID = `DELETE FROM submissions
+WHERE author_id = ? AND submission_id IN (SELECT value FROM json_each(?))`
+
+	insertPurgeAudit = `INSERT INTO purge_audit (audit_id, author_hash, submission_ids, objects_deleted, reports_scrubbed, requested_by, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	listSubmissionsAfterID = `SELECT ` + submissionColumns + ` FROM submissions
+WHERE submission_id > ?
+ORDER BY submission_id
+LIMIT ?`
+
+	updateSubmissionStatus = `UPDATE submissions
+SET status = ?2
+WHERE submission_id = ?1`
+
+	getSubmissionByID = `SELECT ` + submissionColumns + ` FROM submissions
+WHERE submission_id = ? AND deleted_at IS NULL`
+
+	getSubmissionsByAuthorID = `SELECT ` + submissionColumns + ` FROM submissions
+WHERE author_id = ? AND deleted_at IS NULL
+ORDER BY created_at DESC`
+
+	getAuthorUsage = `SELECT
+    COUNT(CASE WHEN assignment_id = ?2 THEN 1 END),
+    COALESCE(SUM(size_bytes), 0)
+FROM submissions
+WHERE author_id = ?1 AND deleted_at IS NULL`
+
+	listSubmissionsByAssignmentIDDesc = `SELECT ` + submissionColumns + ` FROM submissions
+WHERE assignment_id = ?1
+  AND deleted_at IS NULL
+  AND (?2 IS NULL OR created_at >= ?2)
+  AND (?3 IS NULL OR created_at < ?3)
+  AND (?4 IS NULL OR (created_at, submission_id) < (?4, ?5))
+ORDER BY created_at DESC, submission_id DESC
+LIMIT ?6`
+
+	listSubmissionsByAssignmentIDAsc = `SELECT ` + submissionColumns + ` FROM submissions
+WHERE assignment_id = ?1
+  AND deleted_at IS NULL
+  AND (?2 IS NULL OR created_at >= ?2)
+  AND (?3 IS NULL OR created_at < ?3)
+  AND (?4 IS NULL OR (created_at, submission_id) > (?4, ?5))
+ORDER BY created_at ASC, submission_id ASC
+LIMIT ?6`
+)
+
+type sqliteRepository struct {
+	db *sql.DB
+}
+
+// Open creates the database file if needed. The schema is managed by
+// Migrator.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(30000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "foreign_keys(1)")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func NewSQLiteRepository(db *sql.DB) repository.SubmissionRepository {
+	return &sqliteRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubmission(row rowScanner) (*entity.Submission, error) {
+	var (
+		id        string
+		createdAt string
+		deletedAt sql.NullString
+		status    string
+		sub       entity.Submission
+	)
+	if err := row.Scan(&id, &sub.AssignmentID, &sub.AuthorID, &createdAt, &sub.SizeBytes, &sub.Checksum, &deletedAt, &status, &sub.ContentType, &sub.OriginalFilename); err != nil {
+		return nil, err
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid submission_id %q: %w", id, err)
+	}
+	parsedCreatedAt, err := time.Parse(timeLayout, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created_at %q: %w", createdAt, err)
+	}
+
+	if deletedAt.Valid {
+		parsedDeletedAt, err := time.Parse(timeLayout, deletedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deleted_at %q: %w", deletedAt.String, err)
+		}
+		sub.DeletedAt = &parsedDeletedAt
+	}
+
+	sub.SubmissionID = parsedID
+	sub.CreatedAt = parsedCreatedAt
+	sub.Status = entity.SubmissionStatus(status)
+	return &sub, nil
+}
+
+func scanSubmissions(rows *sql.Rows) ([]*entity.Submission, error) {
+	defer rows.Close()
+
+	result := make([]*entity.Submission, 0)
+	for rows.Next() {
+		sub, err := scanSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func formatTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(timeLayout)
+}
+
+func (r *sqliteRepository) Create(ctx context.Context, assignmentID, authorID string) (*entity.Submission, error) {
+	submission, tx, err := r.CreateWithTx(ctx, assignmentID, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to commit submission tx")
+	}
+
+	return submission, nil
+}
+
+func (r *sqliteRepository) CreateWithTx(ctx context.Context, assignmentID, authorID string) (*entity.Submission, repository.Transaction, error) {
+	return r.CreateAtWithTx(ctx, assignmentID, authorID, time.Now())
+}
+
+// CreateAtWithTx does not touch the database yet: the row is inserted when
+// the returned transaction commits (see deferredTx).
+func (r *sqliteRepository) CreateAtWithTx(_ context.Context, assignmentID, authorID string, createdAt time.Time) (*entity.Submission, repository.Transaction, error) {
+	sub := &entity.Submission{
+		SubmissionID: uuid.New(),
+		AssignmentID: assignmentID,
+		AuthorID:     authorID,
+		CreatedAt:    createdAt.UTC().Truncate(time.Microsecond),
+		Status:       entity.SubmissionStatusActive,
+	}
+
+	tx := &deferredTx{db: r.db}
+	tx.add(insertSubmission, "failed to create submission",
+		sub.SubmissionID.String(), sub.AssignmentID, sub.AuthorID, formatTime(&sub.CreatedAt),
+	)
+	return sub, tx, nil
+}
+
+func (r *sqliteRepository) SetFileInfoWithTx(_ context.Context, tx repository.Transaction, submissionID uuid.UUID, info entity.FileInfo) error {
+	deferred, ok := tx.(*deferredTx)
+	if !ok {
+		return apperr.New(apperr.CodeDatabase, "unsupported transaction type")
+	}
+
+	deferred.add(updateSubmissionFileInfo, "failed to update submission file info",
+		submissionID.String(), info.SizeBytes, info.Checksum, info.ContentType, string(info.Status), info.OriginalFilename,
+	)
+	return nil
+}
+
+func (r *sqliteRepository) GetByID(ctx context.Context, submissionID uuid.UUID) (*entity.Submission, error) {
+	sub, err := scanSubmission(r.db.QueryRowContext(ctx, getSubmissionByID, submissionID.String()))
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, apperr.Wrap(err, apperr.CodeNotFound, "submission not found")
+		}
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to get submission by id")
+	}
+
+	return sub, nil
+}
+
+func (r *sqliteRepository) ListByAssignmentID(ctx context.Context, filter repository.ListFilter) ([]*entity.Submission, error) {
+	var (
+		cursorCreatedAt any
+		cursorID        any
+	)
+	if filter.After != nil {
+		cursorCreatedAt = formatTime(&filter.After.CreatedAt)
+		cursorID = filter.After.SubmissionID.String()
+	}
+
+	query := listSubmissionsByAssignmentIDDesc
+	if filter.Sort == repository.SortCreatedAtAsc {
+		query = listSubmissionsByAssignmentIDAsc
+	}
+
+	rows, err := r.db.QueryContext(ctx, query,
+		filter.AssignmentID,
+		formatTime(filter.CreatedAfter),
+		formatTime(filter.CreatedBefore),
+		cursorCreatedAt,
+		cursorID,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions by assignment_id")
+	}
+
+	subs, err := scanSubmissions(rows)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions by assignment_id")
+	}
+	return subs, nil
+}
+
+func (r *sqliteRepository) GetByAuthorID(ctx context.Context, authorID string) ([]*entity.Submission, error) {
+	rows, err := r.db.QueryContext(ctx, getSubmissionsByAuthorID, authorID)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to get submissions by author_id")
+	}
+
+	subs, err := scanSubmissions(rows)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to get submissions by author_id")
+	}
+	return subs, nil
+}
+
+func (r *sqliteRepository) GetUsage(ctx context.Context, authorID, assignmentID string) (*entity.Usage, error) {
+	var usage entity.Usage
+	if err := r.db.QueryRowContext(ctx, getAuthorUsage, authorID, assignmentID).Scan(&usage.AssignmentSubmissions, &usage.BytesUsed); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to get author usage")
+	}
+	return &usage, nil
+}
+
+func (r *sqliteRepository) SoftDelete(ctx context.Context, submissionID uuid.UUID, assignmentID string) (*entity.Submission, error) {
+	submission, tx, err := r.SoftDeleteWithTx(ctx, submissionID, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to commit delete tx")
+	}
+
+	return submission, nil
+}
+
+// SoftDeleteWithTx has to read the row back, so unlike CreateWithTx it opens
+// the transaction right away; callers only add a few statements before
+// committing.
+func (r *sqliteRepository) SoftDeleteWithTx(ctx context.Context, submissionID uuid.UUID, assignmentID string) (*entity.Submission, repository.Transaction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to begin delete tx")
+	}
+
+	deletedAt := time.Now().UTC()
+	sub, err := scanSubmission(tx.QueryRowContext(ctx, softDeleteSubmission,
+		submissionID.String(), assignmentID, formatTime(&deletedAt),
+	))
+	if err != nil {
+		_ = tx.Rollback()
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return nil, nil, apperr.Wrap(err, apperr.CodeNotFound, "submission not found")
+		}
+		return nil, nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to delete submission")
+	}
+
+	return sub, &deferredTx{db: r.db, tx: tx}, nil
+}
+
+func (r *sqliteRepository) ListAfterID(ctx context.Context, after uuid.UUID, limit int) ([]*entity.Submission, error) {
+	rows, err := r.db.QueryContext(ctx, listSubmissionsAfterID, after.String(), limit)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions")
+	}
+
+	subs, err := scanSubmissions(rows)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions")
+	}
+	return subs, nil
+}
+
+func (r *sqliteRepository) SetStatus(ctx context.Context, submissionID uuid.UUID, status entity.SubmissionStatus) error {
+	if _, err := r.db.ExecContext(ctx, updateSubmissionStatus, submissionID.String(), string(status)); err != nil {
+		return apperr.Wrap(err, apperr.CodeDatabase, "failed to update submission status")
+	}
+	return nil
+}
+
+func (r *sqliteRepository) ListAllByAuthorID(ctx context.Context, authorID string) ([]*entity.Submission, error) {
+	rows, err := r.db.QueryContext(ctx, getAllSubmissionsByAuthorID, authorID)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions by author_id")
+	}
+
+	subs, err := scanSubmissions(rows)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to list submissions by author_id")
+	}
+	return subs, nil
+}
+
+func (r *sqliteRepository) PurgeByAuthorID(ctx context.Context, authorID string, audit *entity.PurgeAudit) (*entity.PurgeAudit, error) {
+	submissionIDs, err := json.Marshal(audit.SubmissionIDs)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "failed to encode purged submission ids")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to begin purge tx")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, deleteSubmissionsByAuthorID, authorID, string(submissionIDs)); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to delete submissions by author_id")
+	}
+
+	result := *audit
+	result.AuditID = uuid.New()
+	result.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	if _, err := tx.ExecContext(ctx, insertPurgeAudit,
+		result.AuditID.String(),
+		result.AuthorHash,
+		string(submissionIDs),
+		result.ObjectsDeleted,
+		result.ReportsScrubbed,
+		result.RequestedBy,
+		formatTime(&result.CreatedAt),
+	); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to write purge audit")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDatabase, "failed to commit purge tx")
+	}
+
+	return &result, nil
+}
+
+// deferredTx queues the statements of a submission and runs them in one
+// transaction on Commit. SQLite has a single writer, so a transaction held
+// open for a whole streamed upload would stall every other submit. tx is set
+// when the transaction had to be opened early.
+type deferredTx struct {
+	db         *sql.DB
+	tx         *sql.Tx
+	statements []deferredStatement
+	done       bool
+}
+
+type deferredStatement struct {
+	query   string
+	args    []any
+	failure string
+}
+
+func (t *deferredTx) add(query, failure string, args ...any) {
+	t.statements = append(t.statements, deferredStatement{query: query, args: args, failure: failure})
+}
+
+func (t *deferredTx) Commit(ctx context.Context) error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	tx := t.tx
+	if tx == nil {
+		var err error
+		tx, err = t.db.BeginTx(ctx, nil)
+		if err != nil {
+			return apperr.Wrap(err, apperr.CodeDatabase, "failed to begin submission tx")
+		}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, statement := range t.statements {
+		if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			return apperr.Wrap(err, apperr.CodeDatabase, statement.failure)
+		}
+	}
+	return tx.Commit()
+}
+
+func (t *deferredTx) Rollback(_ context.Context) error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.tx != nil {
+		return t.tx.Rollback()
+	}
+	return nil
+}
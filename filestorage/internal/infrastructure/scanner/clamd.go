@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"filestorage/internal/domain/entity"
+)
+
+// clamd rejects streams over its StreamMaxLength; chunks are far below it.
+const clamdChunkSize = 64 * 1024
+
+// ClamdScanner streams files to a clamd daemon with the INSTREAM command.
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner accepts tcp://host:port, unix:///path/to/clamd.sock or a
+// bare host:port. timeout bounds each network operation, not the whole scan,
+// since uploads arrive at the client's pace.
+func NewClamdScanner(address string, timeout time.Duration) (*ClamdScanner, error) {
+	network, addr := "tcp", address
+	switch {
+	case strings.HasPrefix(address, "tcp://"):
+		addr = strings.TrimPrefix(address, "tcp://")
+	case strings.HasPrefix(address, "unix://"):
+		network, addr = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.Contains(address, "://"):
+		return nil, fmt.Errorf("unsupported clamd address %q", address)
+	}
+	if addr == "" {
+		return nil, errors.New("clamd address is empty")
+	}
+	return &ClamdScanner{network: network, address: addr, timeout: timeout}, nil
+}
+
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (*entity.ScanResult, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := s.write(conn, []byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if err := s.write(conn, buf[:4+n]); err != nil {
+				// clamd may already have answered, e.g. when the stream
+				// exceeded its size limit.
+				if reply, replyErr := s.readReply(conn); replyErr == nil {
+					return parseClamdReply(reply)
+				}
+				return nil, err
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	if err := s.write(conn, []byte{0, 0, 0, 0}); err != nil {
+		return nil, err
+	}
+	reply, err := s.readReply(conn)
+	if err != nil {
+		return nil, err
+	}
+	return parseClamdReply(reply)
+}
+
+func (s *ClamdScanner) write(conn net.Conn, p []byte) error {
+	if s.timeout > 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(s.timeout))
+	}
+	if _, err := conn.Write(p); err != nil {
+		return fmt.Errorf("write to clamd: %w", err)
+	}
+	return nil
+}
+
+func (s *ClamdScanner) readReply(conn net.Conn) (string, error) {
+	if s.timeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(s.timeout))
+	}
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && !(err == io.EOF && len(reply) > 0) {
+		return "", fmt.Errorf("read clamd reply: %w", err)
+	}
+	return string(bytes.TrimRight(reply, "\x00\n")), nil
+}
+
+// parseClamdReply understands "stream: OK", "stream: <signature> FOUND" and
+// "<message> ERROR".
+func parseClamdReply(reply string) (*entity.ScanResult, error) {
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		signature = strings.TrimPrefix(signature, "stream: ")
+		return &entity.ScanResult{Infected: true, Signature: signature}, nil
+	case strings.HasSuffix(reply, ": OK"):
+		return &entity.ScanResult{}, nil
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
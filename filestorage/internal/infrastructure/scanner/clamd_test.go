@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd accepts connections on a local port and hands each one to serve.
+func fakeClamd(t *testing.T, serve func(conn net.Conn)) *ClamdScanner {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+
+	s, err := NewClamdScanner("tcp://"+ln.Addr().String(), 2*time.Second)
+	if err != nil {
+		t.Fatalf("NewClamdScanner: %v", err)
+	}
+	return s
+}
+
+// readInstream reads the command and the chunks up to the terminating one,
+// stopping after maxChunks when it is positive.
+func readInstream(conn net.Conn, maxChunks int) ([]byte, error) {
+	cmd := make([]byte, len("zINSTREAM\x00"))
+	if _, err := io.ReadFull(conn, cmd); err != nil {
+		return nil, err
+	}
+	if string(cmd) != "zINSTREAM\x00" {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	var data bytes.Buffer
+	for chunks := 0; maxChunks <= 0 || chunks < maxChunks; chunks++ {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return nil, err
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n == 0 {
+			break
+		}
+		if _, err := io.CopyN(&data, conn, int64(n)); err != nil {
+			return nil, err
+		}
+	}
+	return data.Bytes(), nil
+}
+
+func TestClamdScanner(t *testing.T) {
+	tests := []struct {
+		name          string
+		reply         string
+		wantInfected  bool
+		wantSignature string
+		wantErr       string
+	}{
+		{name: "clean", reply: "stream: OK"},
+		{name: "infected", reply: "stream: Eicar-Test-Signature FOUND", wantInfected: true, wantSignature: "Eicar-Test-Signature"},
+		{name: "error", reply: "INSTREAM size limit exceeded. ERROR", wantErr: "INSTREAM size limit exceeded. ERROR"},
+	}
+
+	// Spans several chunks, the last one partial.
+	file := bytes.Repeat([]byte("print('hello')\n"), 3*clamdChunkSize/15+7)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan []byte, 1)
+			s := fakeClamd(t, func(conn net.Conn) {
+				data, err := readInstream(conn, 0)
+				if err != nil {
+					return
+				}
+				received <- data
+				_, _ = conn.Write([]byte(tt.reply + "\x00"))
+			})
+
+			result, err := s.Scan(context.Background(), bytes.NewReader(file))
+
+			select {
+			case data := <-received:
+				if !bytes.Equal(data, file) {
+					t.Errorf("clamd received %d bytes, want %d", len(data), len(file))
+				}
+			default:
+				t.Error("clamd did not receive a complete stream")
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Scan() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if result.Infected != tt.wantInfected || result.Signature != tt.wantSignature {
+				t.Errorf("Scan() = %+v, want infected=%t signature=%q", result, tt.wantInfected, tt.wantSignature)
+			}
+		})
+	}
+}
+
+func TestClamdScannerConnectionDropped(t *testing.T) {
+	s := fakeClamd(t, func(conn net.Conn) {
+		_, _ = readInstream(conn, 1)
+	})
+
+	file := bytes.Repeat([]byte{'x'}, 64*clamdChunkSize)
+	result, err := s.Scan(context.Background(), bytes.NewReader(file))
+	if err == nil {
+		t.Fatalf("Scan() = %+v, want an error", result)
+	}
+}
+
+func TestClamdScannerUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	s, err := NewClamdScanner(addr, time.Second)
+	if err != nil {
+		t.Fatalf("NewClamdScanner: %v", err)
+	}
+	if _, err := s.Scan(context.Background(), strings.NewReader("x")); err == nil {
+		t.Fatal("Scan() succeeded without clamd")
+	}
+}
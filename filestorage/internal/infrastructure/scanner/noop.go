@@ -0,0 +1,22 @@
+package scanner
+
+import (
+	"context"
+	"io"
+
+	"filestorage/internal/domain/entity"
+)
+
+// NoopScanner accepts every file.
+type NoopScanner struct{}
+
+func NewNoopScanner() *NoopScanner {
+	return &NoopScanner{}
+}
+
+func (NoopScanner) Scan(ctx context.Context, r io.Reader) (*entity.ScanResult, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, err
+	}
+	return &entity.ScanResult{}, nil
+}
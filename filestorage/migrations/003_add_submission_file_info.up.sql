@@ -0,0 +1,2 @@
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS size_bytes BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS checksum TEXT NOT NULL DEFAULT '';
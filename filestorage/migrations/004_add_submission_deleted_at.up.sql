@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
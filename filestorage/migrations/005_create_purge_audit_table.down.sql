@@ -0,0 +1 @@
+DROP TABLE IF EXISTS purge_audit;
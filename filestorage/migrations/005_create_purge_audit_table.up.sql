@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS purge_audit (
+    audit_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    author_hash TEXT NOT NULL,
+    submission_ids TEXT NOT NULL,
+    objects_deleted INTEGER NOT NULL,
+    reports_scrubbed BOOLEAN NOT NULL,
+    requested_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
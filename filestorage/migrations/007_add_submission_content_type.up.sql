@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS content_type TEXT NOT NULL DEFAULT '';
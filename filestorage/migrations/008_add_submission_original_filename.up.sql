@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS original_filename TEXT NOT NULL DEFAULT '';
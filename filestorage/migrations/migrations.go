@@ -0,0 +1,143 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration is one NNN_name.up.sql file together with its .down.sql pair.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied; AppliedAt is nil
+// for pending ones.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// Applied is a row of the schema_migrations table.
+type Applied struct {
+	Name      string
+	AppliedAt time.Time
+}
+
+// Load reads the migrations in dir of fsys ordered by version.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read embedded migrations: %w", err)
+	}
+
+	var result []Migration
+	seen := make(map[int64]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration name %q", name)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration name %q", name)
+		}
+		base := strings.TrimSuffix(name, ".up.sql")
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %q and %q share version %d", other, base, version)
+		}
+		seen[version] = base
+
+		up, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		down, err := fs.ReadFile(fsys, path.Join(dir, base+".down.sql"))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no down file: %w", base, err)
+		}
+		result = append(result, Migration{
+			Version: version,
+			Name:    base,
+			Up:      string(up),
+			Down:    string(down),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Pending returns the migrations that are not in applied, in order.
+func Pending(all []Migration, applied map[int64]Applied) []Migration {
+	var result []Migration
+	for _, m := range all {
+		if _, ok := applied[m.Version]; !ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// Rollback returns the last steps applied migrations, newest first. It fails
+// when the database holds a version this binary does not know, since there
+// is no down file to run for it.
+func Rollback(all []Migration, applied map[int64]Applied, steps int) ([]Migration, error) {
+	byVersion := make(map[int64]Migration, len(all))
+	for _, m := range all {
+		byVersion[m.Version] = m
+	}
+
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	var result []Migration
+	for _, version := range versions {
+		if len(result) == steps {
+			break
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("applied migration %d is unknown to this build", version)
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// Statuses lists every known migration and any applied version this binary
+// does not know, ordered by version.
+func Statuses(all []Migration, applied map[int64]Applied) []Status {
+	result := make([]Status, 0, len(all))
+	known := make(map[int64]bool, len(all))
+	for _, m := range all {
+		known[m.Version] = true
+		s := Status{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			s.AppliedAt = &a.AppliedAt
+		}
+		result = append(result, s)
+	}
+	for version, a := range applied {
+		if !known[version] {
+			result = append(result, Status{Version: version, Name: a.Name, AppliedAt: &a.AppliedAt})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result
+}
@@ -0,0 +1,9 @@
+CREATE TABLE submissions (
+    submission_id TEXT PRIMARY KEY,
+    assignment_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_submissions_assignment_id ON submissions(assignment_id);
+CREATE INDEX idx_submissions_author_id ON submissions(author_id);
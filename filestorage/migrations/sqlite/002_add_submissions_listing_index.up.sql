@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_submissions_assignment_created_at
+    ON submissions(assignment_id, created_at, submission_id);
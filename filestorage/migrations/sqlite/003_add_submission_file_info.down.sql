@@ -0,0 +1,2 @@
+ALTER TABLE submissions DROP COLUMN checksum;
+ALTER TABLE submissions DROP COLUMN size_bytes;
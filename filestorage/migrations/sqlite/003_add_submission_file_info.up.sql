@@ -0,0 +1,2 @@
+ALTER TABLE submissions ADD COLUMN size_bytes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE submissions ADD COLUMN checksum TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE submissions DROP COLUMN deleted_at;
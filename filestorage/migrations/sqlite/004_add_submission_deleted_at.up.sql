@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN deleted_at TEXT;
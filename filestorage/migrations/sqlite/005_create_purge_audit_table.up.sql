@@ -0,0 +1,9 @@
+CREATE TABLE purge_audit (
+    audit_id TEXT PRIMARY KEY,
+    author_hash TEXT NOT NULL,
+    submission_ids TEXT NOT NULL,
+    objects_deleted INTEGER NOT NULL,
+    reports_scrubbed INTEGER NOT NULL,
+    requested_by TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
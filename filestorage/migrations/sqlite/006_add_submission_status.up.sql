@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN content_type TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE submissions ADD COLUMN original_filename TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+CREATE TABLE outbox_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_type TEXT NOT NULL,
+    assignment_id TEXT NOT NULL,
+    submission_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    available_at TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_outbox_events_available_at ON outbox_events(available_at, id);
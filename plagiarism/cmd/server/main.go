@@ -13,20 +13,41 @@ import (
 	"plagiarism/internal/application/usecase"
 	"plagiarism/internal/domain"
 	"plagiarism/internal/infrastructure/config"
+	"plagiarism/internal/infrastructure/events"
 	"plagiarism/internal/infrastructure/filestorage"
 	"plagiarism/internal/infrastructure/report"
+	"plagiarism/internal/infrastructure/webhook"
 	"plagiarism/internal/infrastructure/worker"
 )
 
 func main() {
 	reportStore := report.NewFileReportStore("plagiarism/reports")
-	fsClient := filestorage.NewClient(config.FilestorageURL())
+	broker := events.NewBroker()
+	webhookStore, err := webhook.NewFileStore("plagiarism/webhooks")
+	if err != nil {
+		log.Fatalf("failed to load webhooks: %v", err)
+	}
+	guard := webhook.NewGuard(config.WebhookAllowedHosts())
+	dispatcher := webhook.NewDispatcher(webhookStore, config.WebhookMaxAttempts(), config.WebhookRetryBase(), guard)
+	if err := dispatcher.Resume(); err != nil {
+		log.Fatalf("failed to resume webhook deliveries: %v", err)
+	}
+	notifier := worker.Notifiers{broker, dispatcher}
+	fsClient := filestorage.NewClient(config.FilestorageURL(), config.DownloadCacheBytes())
 	w := worker.NewWorker(reportStore, fsClient, config.MatchThreshold(), config.WorkerCount(), func(rep domain.CheckReport, err error) {
 		log.Printf("failed to save report work=%s submission=%s: %v", rep.WorkID, rep.SubmissionID, err)
-	})
-	checkUseCase := usecase.NewCheckService(reportStore, w)
+	}, notifier)
+	checkUseCase := usecase.NewCheckService(reportStore, w, notifier)
+	resumed, err := checkUseCase.ResumeChecks(context.Background())
+	if err != nil {
+		log.Fatalf("failed to resume checks: %v", err)
+	}
+	if resumed > 0 {
+		log.Printf("resumed %d checks left pending by the last run", resumed)
+	}
+	webhookUseCase := usecase.NewWebhookService(webhookStore, dispatcher, guard)
 
-	r := router.NewRouter(checkUseCase)
+	r := router.NewRouter(checkUseCase, broker, webhookUseCase, config.AdminToken())
 	handler := r.SetupRoutes()
 
 	port := config.ServerPort()
@@ -35,6 +56,8 @@ func main() {
 		Addr:    addr,
 		Handler: handler,
 	}
+	// Event streams never go idle on their own.
+	srv.RegisterOnShutdown(broker.Close)
 
 	go func() {
 		log.Printf("plagiarism service starting on %s", addr)
@@ -57,5 +80,6 @@ func main() {
 	}
 
 	w.Close()
+	dispatcher.Close()
 	log.Println("plagiarism service stopped")
 }
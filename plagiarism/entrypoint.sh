@@ -1,8 +1,8 @@
 #!/bin/sh
 set -e
 
-# Ensure reports dir is writable when mounted as a volume.
-chown -R appuser:appuser /app/plagiarism/reports 2>/dev/null || true
+# Ensure reports and webhooks dirs are writable when mounted as volumes.
+chown -R appuser:appuser /app/plagiarism/reports /app/plagiarism/webhooks 2>/dev/null || true
 
 # Drop privileges to appuser if setpriv is available; otherwise run as root.
 if command -v setpriv >/dev/null 2>&1; then
@@ -38,6 +38,8 @@ func mapAPIError(err error) apiError {
 		return apiError{status: http.StatusBadRequest, code: code, message: message}
 	case apperr.CodeNotFound:
 		return apiError{status: http.StatusNotFound, code: code, message: message}
+	case apperr.CodeForbidden:
+		return apiError{status: http.StatusForbidden, code: code, message: message}
 	case apperr.CodeStorage:
 		return apiError{status: http.StatusBadGateway, code: code, message: message}
 	default:
@@ -51,6 +53,8 @@ func defaultMessageForCode(code apperr.Code) string {
 		return "validation error"
 	case apperr.CodeNotFound:
 		return "report not found"
+	case apperr.CodeForbidden:
+		return "forbidden"
 	case apperr.CodeStorage:
 		return "storage error"
 	default:
@@ -59,3 +59,52 @@ func (h *CheckHandler) handleStart(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(resp)
 }
+
+func (h *CheckHandler) HandleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, "only POST is allowed")
+		return
+	}
+
+	var request struct {
+		WorkID        string   `json:"work_id"`
+		SubmissionIDs []string `json:"submission_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		respondValidationError(w, "failed to parse request body")
+		return
+	}
+
+	if request.WorkID == "" {
+		respondValidationError(w, "work_id is required")
+		return
+	}
+
+	if len(request.SubmissionIDs) == 0 {
+		respondValidationError(w, "submission_ids is required")
+		return
+	}
+
+	for _, id := range request.SubmissionIDs {
+		if id == "" {
+			respondValidationError(w, "submission_ids must not contain empty ids")
+			return
+		}
+	}
+
+	if h.useCase == nil {
+		respondError(w, usecase.ErrWorkerUnavailable)
+		return
+	}
+
+	resp, err := h.useCase.StartChecks(r.Context(), request.WorkID, request.SubmissionIDs)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"plagiarism/internal/domain"
+)
+
+// heartbeatInterval keeps idle streams from being cut by proxies.
+const heartbeatInterval = 15 * time.Second
+
+type EventSource interface {
+	Subscribe(workID string) (<-chan domain.CheckEvent, func())
+}
+
+type EventsHandler struct {
+	source EventSource
+}
+
+func NewEventsHandler(source EventSource) *EventsHandler {
+	return &EventsHandler{source: source}
+}
+
+// Handle streams the check status changes of a work as Server-Sent Events.
+// Only changes after the client connected are sent.
+func (h *EventsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET method is allowed")
+		return
+	}
+
+	workID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/works/"), "/events")
+	if !ok || workID == "" || strings.Contains(workID, "/") {
+		respondValidationError(w, "work_id is required in path")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondValidationError(w, "streaming is not supported")
+		return
+	}
+
+	events, cancel := h.source.Subscribe(workID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: check\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
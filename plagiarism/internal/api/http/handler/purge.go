@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"plagiarism/internal/application/dto"
+	"plagiarism/internal/application/usecase"
+)
+
+type PurgeHandler struct {
+	useCase usecase.CheckUseCase
+}
+
+func NewPurgeHandler(uc usecase.CheckUseCase) *PurgeHandler {
+	return &PurgeHandler{useCase: uc}
+}
+
+// Handle serves POST /purges, which filestorage calls when all data of an
+// author is erased.
+func (h *PurgeHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w, "only POST is allowed")
+		return
+	}
+
+	var request dto.PurgeAuthorRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		respondValidationError(w, "failed to parse request body")
+		return
+	}
+
+	if request.AuthorID == "" {
+		respondValidationError(w, "author_id is required")
+		return
+	}
+
+	if h.useCase == nil {
+		respondError(w, usecase.ErrWorkerUnavailable)
+		return
+	}
+
+	resp, err := h.useCase.PurgeAuthor(r.Context(), request)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"plagiarism/internal/application/usecase"
+)
+
+type SubmissionsHandler struct {
+	useCase usecase.CheckUseCase
+}
+
+func NewSubmissionsHandler(uc usecase.CheckUseCase) *SubmissionsHandler {
+	return &SubmissionsHandler{useCase: uc}
+}
+
+// Handle serves /works/{work_id}/submissions/{submission_id}: GET returns
+// the submission's check, DELETE is called by filestorage after the
+// submission has been deleted.
+func (h *SubmissionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		respondMethodNotAllowed(w, "only GET and DELETE methods are allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/works/")
+	workID, submissionID, ok := strings.Cut(path, "/submissions/")
+	if !ok || workID == "" || submissionID == "" || strings.Contains(submissionID, "/") {
+		respondValidationError(w, "expected /works/{work_id}/submissions/{submission_id}")
+		return
+	}
+
+	if h.useCase == nil {
+		respondError(w, usecase.ErrWorkerUnavailable)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		resp, err := h.useCase.GetCheck(r.Context(), workID, submissionID)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	if err := h.useCase.DeleteSubmission(r.Context(), workID, submissionID); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
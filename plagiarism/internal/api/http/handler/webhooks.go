@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"plagiarism/internal/application/dto"
+	"plagiarism/internal/application/usecase"
+)
+
+type WebhooksHandler struct {
+	useCase    usecase.WebhookUseCase
+	collection http.HandlerFunc
+	item       http.HandlerFunc
+}
+
+// NewWebhooksHandler requires the admin token on every webhook route: a
+// subscription receives the reports of whole works.
+func NewWebhooksHandler(uc usecase.WebhookUseCase, adminToken string) *WebhooksHandler {
+	h := &WebhooksHandler{useCase: uc}
+	h.collection = requireAdmin(adminToken, h.handleCollection)
+	h.item = requireAdmin(adminToken, h.handleItem)
+	return h
+}
+
+// HandleCollection serves /webhooks: GET lists, POST subscribes.
+func (h *WebhooksHandler) HandleCollection(w http.ResponseWriter, r *http.Request) {
+	h.collection(w, r)
+}
+
+// HandleItem serves /webhooks/{id}, /webhooks/{id}/deliveries and
+// /webhooks/{id}/test.
+func (h *WebhooksHandler) HandleItem(w http.ResponseWriter, r *http.Request) {
+	h.item(w, r)
+}
+
+func (h *WebhooksHandler) handleCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		resp, err := h.useCase.List(r.Context(), r.URL.Query().Get("work_id"))
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case http.MethodPost:
+		var request dto.CreateWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			respondValidationError(w, "failed to parse request body")
+			return
+		}
+		if request.URL == "" {
+			respondValidationError(w, "url is required")
+			return
+		}
+		hook, err := h.useCase.Create(r.Context(), request)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, hook)
+	default:
+		respondMethodNotAllowed(w, "only GET and POST are allowed")
+	}
+}
+
+func (h *WebhooksHandler) handleItem(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/webhooks/"), "/")
+	if id == "" {
+		respondValidationError(w, "webhook id is required in path")
+		return
+	}
+
+	switch action {
+	case "":
+		h.handleWebhook(w, r, id)
+	case "deliveries":
+		if r.Method != http.MethodGet {
+			respondMethodNotAllowed(w, "only GET is allowed")
+			return
+		}
+		resp, err := h.useCase.Deliveries(r.Context(), id)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case "test":
+		if r.Method != http.MethodPost {
+			respondMethodNotAllowed(w, "only POST is allowed")
+			return
+		}
+		delivery, err := h.useCase.Test(r.Context(), id)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, delivery)
+	default:
+		respondValidationError(w, "invalid path format")
+	}
+}
+
+func (h *WebhooksHandler) handleWebhook(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodGet:
+		hook, err := h.useCase.Get(r.Context(), id)
+		if err != nil {
+			respondError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, hook)
+	case http.MethodDelete:
+		if err := h.useCase.Delete(r.Context(), id); err != nil {
+			respondError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		respondMethodNotAllowed(w, "only GET and DELETE are allowed")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -2,20 +2,29 @@ package router
 
 import (
 	"net/http"
+	"strings"
 
 	"plagiarism/internal/api/http/handler"
 	"plagiarism/internal/application/usecase"
 )
 
 type Router struct {
-	checkHandler   *handler.CheckHandler
-	reportsHandler *handler.ReportsHandler
+	checkHandler       *handler.CheckHandler
+	reportsHandler     *handler.ReportsHandler
+	submissionsHandler *handler.SubmissionsHandler
+	purgeHandler       *handler.PurgeHandler
+	eventsHandler      *handler.EventsHandler
+	webhooksHandler    *handler.WebhooksHandler
 }
 
-func NewRouter(checkUseCase usecase.CheckUseCase) *Router {
+func NewRouter(checkUseCase usecase.CheckUseCase, events handler.EventSource, webhookUseCase usecase.WebhookUseCase, adminToken string) *Router {
 	return &Router{
-		checkHandler:   handler.NewCheckHandler(checkUseCase),
-		reportsHandler: handler.NewReportsHandler(checkUseCase),
+		checkHandler:       handler.NewCheckHandler(checkUseCase),
+		reportsHandler:     handler.NewReportsHandler(checkUseCase),
+		submissionsHandler: handler.NewSubmissionsHandler(checkUseCase),
+		purgeHandler:       handler.NewPurgeHandler(checkUseCase),
+		eventsHandler:      handler.NewEventsHandler(events),
+		webhooksHandler:    handler.NewWebhooksHandler(webhookUseCase, adminToken),
 	}
 }
 
@@ -23,11 +32,27 @@ func (r *Router) SetupRoutes() http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/checks", r.checkHandler.Handle)
-	mux.HandleFunc("/works/", r.reportsHandler.Handle)
+	mux.HandleFunc("/checks/batch", r.checkHandler.HandleBatch)
+	mux.HandleFunc("/works/", r.handleWorks)
+	mux.HandleFunc("/purges", r.purgeHandler.Handle)
+	mux.HandleFunc("/webhooks", r.webhooksHandler.HandleCollection)
+	mux.HandleFunc("/webhooks/", r.webhooksHandler.HandleItem)
 
 	return corsMiddleware(mux)
 }
 
+func (r *Router) handleWorks(w http.ResponseWriter, req *http.Request) {
+	if strings.Contains(req.URL.Path, "/submissions/") {
+		r.submissionsHandler.Handle(w, req)
+		return
+	}
+	if strings.HasSuffix(req.URL.Path, "/events") {
+		r.eventsHandler.Handle(w, req)
+		return
+	}
+	r.reportsHandler.Handle(w, req)
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -7,6 +7,11 @@ type StartCheckResponse struct {
 	Status       string `json:"status"`
 }
 
+type StartChecksResponse struct {
+	WorkID string               `json:"work_id"`
+	Checks []StartCheckResponse `json:"checks"`
+}
+
 type CheckStatusResponse struct {
 	CheckReport domain.CheckReport `json:"report"`
 }
@@ -15,3 +20,18 @@ type WorkReportsResponse struct {
 	WorkID  string               `json:"work_id"`
 	Reports []domain.CheckReport `json:"reports"`
 }
+
+type PurgeSubmission struct {
+	WorkID       string `json:"work_id"`
+	SubmissionID string `json:"submission_id"`
+}
+
+type PurgeAuthorRequest struct {
+	AuthorID    string            `json:"author_id"`
+	Submissions []PurgeSubmission `json:"submissions"`
+}
+
+type PurgeAuthorResponse struct {
+	ReportsDeleted  int `json:"reports_deleted"`
+	ReportsScrubbed int `json:"reports_scrubbed"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "plagiarism/internal/domain"
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	WorkID string   `json:"work_id"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+type WebhooksResponse struct {
+	Webhooks []domain.Webhook `json:"webhooks"`
+}
+
+type WebhookDeliveriesResponse struct {
+	WebhookID  string                   `json:"webhook_id"`
+	Deliveries []domain.WebhookDelivery `json:"deliveries"`
+}
@@ -13,26 +13,41 @@ import (
 
 type reportStore interface {
 	Save(domain.CheckReport) error
+	Create(domain.CheckReport) (domain.CheckReport, bool, error)
+	Pending() ([]domain.CheckReport, error)
 	LoadBySubmissionID(workID, submissionID string) (domain.CheckReport, error)
 	GetOverallByWork(workID string) ([]domain.CheckReport, error)
+	DeleteSubmission(workID, submissionID string) error
+	PurgeAuthor(authorID string, submissions []report.PurgeSubmission) (int, int, error)
 }
 
 type worker interface {
 	Enqueue(ctx context.Context, report domain.CheckReport) error
+	EnqueueBatch(ctx context.Context, reports []domain.CheckReport) error
+}
+
+type notifier interface {
+	Notify(domain.CheckReport)
 }
 
 type CheckService struct {
-	store  reportStore
-	worker worker
+	store    reportStore
+	worker   worker
+	notifier notifier
 }
 
 var ErrCheckNotFound = apperr.New(apperr.CodeNotFound, "report not found")
 var ErrWorkerUnavailable = apperr.New(apperr.CodeInternal, "worker not configured")
 
-func NewCheckService(store reportStore, worker worker) *CheckService {
-	return &CheckService{store: store, worker: worker}
+// NewCheckService builds the service; notifier, if not nil, is told about
+// the reports the service itself saves, the worker reports the rest.
+func NewCheckService(store reportStore, worker worker, notifier notifier) *CheckService {
+	return &CheckService{store: store, worker: worker, notifier: notifier}
 }
 
+// StartCheck queues a check of the submission. A submission is checked only
+// once: starting it again reports the existing check instead, unless that
+// check failed.
 func (s *CheckService) StartCheck(ctx context.Context, submissionID, workID string) (*dto.StartCheckResponse, error) {
 	report := domain.CheckReport{
 		WorkID:       workID,
@@ -44,18 +59,26 @@ func (s *CheckService) StartCheck(ctx context.Context, submissionID, workID stri
 	if s.worker == nil {
 		report.Status = domain.CheckStatusFailed
 		report.Error = ErrWorkerUnavailable.Error()
-		_ = s.store.Save(report)
+		s.save(report)
 		return nil, ErrWorkerUnavailable
 	}
 
-	if err := s.store.Save(report); err != nil {
+	current, created, err := s.store.Create(report)
+	if err != nil {
 		return nil, apperr.Wrap(err, apperr.CodeInternal, "save report failed")
 	}
+	if !created {
+		return &dto.StartCheckResponse{
+			SubmissionID: submissionID,
+			Status:       string(current.Status),
+		}, nil
+	}
+	s.notify(report)
 
 	if err := s.worker.Enqueue(ctx, report); err != nil {
 		report.Status = domain.CheckStatusFailed
 		report.Error = err.Error()
-		_ = s.store.Save(report)
+		s.save(report)
 		return nil, apperr.Wrap(err, apperr.CodeInternal, "enqueue failed")
 	}
 
@@ -65,6 +88,92 @@ func (s *CheckService) StartCheck(ctx context.Context, submissionID, workID stri
 	}, nil
 }
 
+// StartChecks queues checks for a batch of submissions at once, e.g. after
+// an import, so that every check already sees the whole batch. As with
+// StartCheck, submissions that already have a check that did not fail are
+// left alone.
+func (s *CheckService) StartChecks(ctx context.Context, workID string, submissionIDs []string) (*dto.StartChecksResponse, error) {
+	if s.worker == nil {
+		return nil, ErrWorkerUnavailable
+	}
+
+	now := time.Now().UTC()
+	reports := make([]domain.CheckReport, 0, len(submissionIDs))
+	resp := &dto.StartChecksResponse{
+		WorkID: workID,
+		Checks: make([]dto.StartCheckResponse, 0, len(submissionIDs)),
+	}
+	for _, submissionID := range submissionIDs {
+		report := domain.CheckReport{
+			WorkID:       workID,
+			SubmissionID: submissionID,
+			Status:       domain.CheckStatusPending,
+			CreatedAt:    now,
+		}
+		current, created, err := s.store.Create(report)
+		if err != nil {
+			return nil, apperr.Wrap(err, apperr.CodeInternal, "save report failed")
+		}
+		if created {
+			reports = append(reports, report)
+			s.notify(report)
+		}
+		resp.Checks = append(resp.Checks, dto.StartCheckResponse{
+			SubmissionID: submissionID,
+			Status:       string(current.Status),
+		})
+	}
+
+	if len(reports) == 0 {
+		return resp, nil
+	}
+	if err := s.worker.EnqueueBatch(ctx, reports); err != nil {
+		for _, report := range reports {
+			report.Status = domain.CheckStatusFailed
+			report.Error = err.Error()
+			s.save(report)
+		}
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "enqueue failed")
+	}
+
+	return resp, nil
+}
+
+// ResumeChecks queues the checks that were still pending when the service
+// stopped. Starting a check again only reruns failed ones, so without this
+// their reports would stay pending for good.
+func (s *CheckService) ResumeChecks(ctx context.Context) (int, error) {
+	if s.worker == nil {
+		return 0, nil
+	}
+
+	pending, err := s.store.Pending()
+	if err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeInternal, "list pending reports failed")
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	if err := s.worker.EnqueueBatch(ctx, pending); err != nil {
+		return 0, apperr.Wrap(err, apperr.CodeInternal, "enqueue failed")
+	}
+	return len(pending), nil
+}
+
+// save records a check that failed before reaching the worker; the caller
+// already reports the failure, so a save error is not returned.
+func (s *CheckService) save(report domain.CheckReport) {
+	if err := s.store.Save(report); err == nil {
+		s.notify(report)
+	}
+}
+
+func (s *CheckService) notify(report domain.CheckReport) {
+	if s.notifier != nil {
+		s.notifier.Notify(report)
+	}
+}
+
 func (s *CheckService) GetCheck(ctx context.Context, workID, submissionID string) (*dto.CheckStatusResponse, error) {
 	rep, err := s.store.LoadBySubmissionID(workID, submissionID)
 	if err != nil {
@@ -91,3 +200,30 @@ func (s *CheckService) GetReportsByWork(ctx context.Context, workID string) (*dt
 		Reports: reports,
 	}, nil
 }
+
+func (s *CheckService) DeleteSubmission(ctx context.Context, workID, submissionID string) error {
+	if err := s.store.DeleteSubmission(workID, submissionID); err != nil {
+		return apperr.Wrap(err, apperr.CodeInternal, "delete submission reports failed")
+	}
+	return nil
+}
+
+func (s *CheckService) PurgeAuthor(ctx context.Context, req dto.PurgeAuthorRequest) (*dto.PurgeAuthorResponse, error) {
+	submissions := make([]report.PurgeSubmission, 0, len(req.Submissions))
+	for _, sub := range req.Submissions {
+		submissions = append(submissions, report.PurgeSubmission{
+			WorkID:       sub.WorkID,
+			SubmissionID: sub.SubmissionID,
+		})
+	}
+
+	deleted, scrubbed, err := s.store.PurgeAuthor(req.AuthorID, submissions)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "purge author reports failed")
+	}
+
+	return &dto.PurgeAuthorResponse{
+		ReportsDeleted:  deleted,
+		ReportsScrubbed: scrubbed,
+	}, nil
+}
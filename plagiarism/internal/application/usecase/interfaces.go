@@ -9,8 +9,20 @@ import (
 
 type CheckUseCase interface {
 	StartCheck(ctx context.Context, submissionID, workID string) (*dto.StartCheckResponse, error)
+	StartChecks(ctx context.Context, workID string, submissionIDs []string) (*dto.StartChecksResponse, error)
 	GetCheck(ctx context.Context, workID, submissionID string) (*dto.CheckStatusResponse, error)
 	GetReportsByWork(ctx context.Context, workID string) (*dto.WorkReportsResponse, error)
+	DeleteSubmission(ctx context.Context, workID, submissionID string) error
+	PurgeAuthor(ctx context.Context, req dto.PurgeAuthorRequest) (*dto.PurgeAuthorResponse, error)
+}
+
+type WebhookUseCase interface {
+	Create(ctx context.Context, req dto.CreateWebhookRequest) (*domain.Webhook, error)
+	List(ctx context.Context, workID string) (*dto.WebhooksResponse, error)
+	Get(ctx context.Context, id string) (*domain.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	Deliveries(ctx context.Context, id string) (*dto.WebhookDeliveriesResponse, error)
+	Test(ctx context.Context, id string) (*domain.WebhookDelivery, error)
 }
 
 var (
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"plagiarism/internal/application/dto"
+	apperr "plagiarism/internal/common/errors"
+	"plagiarism/internal/domain"
+	"plagiarism/internal/infrastructure/webhook"
+)
+
+type webhookStore interface {
+	Create(domain.Webhook) error
+	List() ([]domain.Webhook, error)
+	Get(id string) (domain.Webhook, error)
+	Delete(id string) error
+	Deliveries(webhookID string) ([]domain.WebhookDelivery, error)
+}
+
+type webhookTester interface {
+	Test(ctx context.Context, hook domain.Webhook) (domain.WebhookDelivery, error)
+}
+
+type destinationChecker interface {
+	CheckURL(ctx context.Context, rawURL string) error
+}
+
+type WebhookService struct {
+	store        webhookStore
+	tester       webhookTester
+	destinations destinationChecker
+}
+
+var ErrWebhookNotFound = apperr.New(apperr.CodeNotFound, "webhook not found")
+
+var webhookEvents = []string{domain.WebhookEventReportCompleted, domain.WebhookEventMatchFound}
+
+// NewWebhookService builds the service; destinations vets subscribed URLs,
+// which the dispatcher enforces again on every delivery.
+func NewWebhookService(store webhookStore, tester webhookTester, destinations destinationChecker) *WebhookService {
+	return &WebhookService{store: store, tester: tester, destinations: destinations}
+}
+
+// Create subscribes a URL to the results of one work, or of every work when
+// WorkID is empty. The secret is generated unless given, and is returned
+// only here.
+func (s *WebhookService) Create(ctx context.Context, req dto.CreateWebhookRequest) (*domain.Webhook, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, apperr.New(apperr.CodeValidation, "url must be an absolute http or https url")
+	}
+	if err := s.destinations.CheckURL(ctx, req.URL); err != nil {
+		if errors.Is(err, webhook.ErrForbiddenDestination) {
+			return nil, apperr.Wrap(err, apperr.CodeValidation, "url must not point to a loopback, private or link-local address")
+		}
+		return nil, apperr.Wrap(err, apperr.CodeValidation, "url host cannot be resolved")
+	}
+
+	events := req.Events
+	if len(events) == 0 {
+		events = webhookEvents
+	}
+	for _, event := range events {
+		if event != domain.WebhookEventReportCompleted && event != domain.WebhookEventMatchFound {
+			return nil, apperr.New(apperr.CodeValidation, "unknown event "+event)
+		}
+	}
+
+	id, err := webhook.NewID(16)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "generate webhook id failed")
+	}
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = webhook.NewID(32); err != nil {
+			return nil, apperr.Wrap(err, apperr.CodeInternal, "generate webhook secret failed")
+		}
+	}
+
+	hook := domain.Webhook{
+		ID:        id,
+		URL:       req.URL,
+		WorkID:    req.WorkID,
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.store.Create(hook); err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "save webhook failed")
+	}
+	return &hook, nil
+}
+
+// List returns the webhooks that fire for workID (its own and the global
+// ones), or all of them when workID is empty.
+func (s *WebhookService) List(ctx context.Context, workID string) (*dto.WebhooksResponse, error) {
+	hooks, err := s.store.List()
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "list webhooks failed")
+	}
+
+	resp := &dto.WebhooksResponse{Webhooks: make([]domain.Webhook, 0, len(hooks))}
+	for _, hook := range hooks {
+		if workID != "" && hook.WorkID != "" && hook.WorkID != workID {
+			continue
+		}
+		resp.Webhooks = append(resp.Webhooks, redactWebhook(hook))
+	}
+	return resp, nil
+}
+
+func (s *WebhookService) Get(ctx context.Context, id string) (*domain.Webhook, error) {
+	hook, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	hook = redactWebhook(hook)
+	return &hook, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id string) error {
+	if err := s.store.Delete(id); err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return ErrWebhookNotFound
+		}
+		return apperr.Wrap(err, apperr.CodeInternal, "delete webhook failed")
+	}
+	return nil
+}
+
+func (s *WebhookService) Deliveries(ctx context.Context, id string) (*dto.WebhookDeliveriesResponse, error) {
+	deliveries, err := s.store.Deliveries(id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "list deliveries failed")
+	}
+	return &dto.WebhookDeliveriesResponse{WebhookID: id, Deliveries: deliveries}, nil
+}
+
+// Test sends a webhook.test event right away; the outcome is returned and
+// logged like any other delivery.
+func (s *WebhookService) Test(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	hook, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.tester.Test(ctx, hook)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeInternal, "test webhook failed")
+	}
+	return &delivery, nil
+}
+
+func (s *WebhookService) get(id string) (domain.Webhook, error) {
+	hook, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrWebhookNotFound) {
+			return domain.Webhook{}, ErrWebhookNotFound
+		}
+		return domain.Webhook{}, apperr.Wrap(err, apperr.CodeInternal, "get webhook failed")
+	}
+	return hook, nil
+}
+
+func redactWebhook(hook domain.Webhook) domain.Webhook {
+	hook.Secret = ""
+	return hook
+}
@@ -9,6 +9,7 @@ type Code string
 const (
 	CodeValidation Code = "validation_error"
 	CodeNotFound   Code = "not_found"
+	CodeForbidden  Code = "forbidden"
 	CodeStorage    Code = "storage_error"
 	CodeInternal   Code = "internal_error"
 )
@@ -19,6 +19,7 @@ type MatchResult struct {
 	Similarity        float64 `json:"similarity"`
 	SelfSize          int64   `json:"self_size"`
 	OtherSize         int64   `json:"other_size"`
+	OtherDeleted      bool    `json:"other_deleted,omitempty"`
 }
 
 type CheckReport struct {
@@ -30,3 +31,14 @@ type CheckReport struct {
 	Error        string        `json:"error,omitempty"`
 	Matches      []MatchResult `json:"matches"`
 }
+
+// CheckEvent is a status change of one check, streamed to whoever watches
+// the work.
+type CheckEvent struct {
+	WorkID       string      `json:"work_id"`
+	SubmissionID string      `json:"submission_id"`
+	AuthorID     string      `json:"author_id,omitempty"`
+	Status       CheckStatus `json:"status"`
+	Error        string      `json:"error,omitempty"`
+	At           time.Time   `json:"at"`
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func FilestorageURL() string {
@@ -36,3 +38,53 @@ func ServerPort() string {
 	}
 	return "8081"
 }
+
+// DownloadCacheBytes bounds the in-memory cache of downloaded submissions.
+// Zero disables it.
+func DownloadCacheBytes() int64 {
+	if v := os.Getenv("DOWNLOAD_CACHE_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return 64 << 20
+}
+
+// WebhookMaxAttempts is how many times a webhook delivery is tried before
+// it is logged as failed.
+func WebhookMaxAttempts() int {
+	if v := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 6
+}
+
+// WebhookRetryBase is the wait before the first retry; it doubles with
+// every further attempt.
+func WebhookRetryBase() time.Duration {
+	if v := os.Getenv("WEBHOOK_RETRY_BASE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 5 * time.Second
+}
+
+// AdminToken guards the /webhooks routes. Empty disables them.
+func AdminToken() string {
+	return os.Getenv("ADMIN_TOKEN")
+}
+
+// WebhookAllowedHosts lists hosts webhooks may be sent to even though they
+// resolve to loopback, private or link-local addresses.
+func WebhookAllowedHosts() []string {
+	var hosts []string
+	for _, host := range strings.Split(os.Getenv("WEBHOOK_ALLOWED_HOSTS"), ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,100 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"plagiarism/internal/domain"
+)
+
+// subscriberBuffer is how far a stream may fall behind before it is dropped;
+// clients reconnect and re-read the reports instead of missing events.
+const subscriberBuffer = 64
+
+type subscriber struct {
+	ch chan domain.CheckEvent
+}
+
+// Broker fans check status changes out to the streams watching each work.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[string]map[*subscriber]struct{}
+	closed bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[string]map[*subscriber]struct{})}
+}
+
+// Notify publishes the current status of report to the work's subscribers.
+func (b *Broker) Notify(report domain.CheckReport) {
+	event := domain.CheckEvent{
+		WorkID:       report.WorkID,
+		SubmissionID: report.SubmissionID,
+		AuthorID:     report.AuthorID,
+		Status:       report.Status,
+		Error:        report.Error,
+		At:           time.Now().UTC(),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs[report.WorkID] {
+		select {
+		case sub.ch <- event:
+		default:
+			b.removeLocked(report.WorkID, sub)
+		}
+	}
+}
+
+// Subscribe returns the events of workID until cancel is called, the
+// subscriber falls behind or the broker is closed; the channel is closed in
+// all three cases.
+func (b *Broker) Subscribe(workID string) (<-chan domain.CheckEvent, func()) {
+	sub := &subscriber{ch: make(chan domain.CheckEvent, subscriberBuffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	if b.subs[workID] == nil {
+		b.subs[workID] = make(map[*subscriber]struct{})
+	}
+	b.subs[workID][sub] = struct{}{}
+
+	return sub.ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.removeLocked(workID, sub)
+	}
+}
+
+// Close ends every stream so that server shutdown does not wait on them.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for workID, subs := range b.subs {
+		for sub := range subs {
+			b.removeLocked(workID, sub)
+		}
+	}
+}
+
+func (b *Broker) removeLocked(workID string, sub *subscriber) {
+	subs, ok := b.subs[workID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(b.subs, workID)
+	}
+}
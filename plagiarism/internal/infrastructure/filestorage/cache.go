@@ -0,0 +1,76 @@
+package filestorage
+
+import (
+	"container/list"
+	"sync"
+)
+
+type cachedFile struct {
+	submissionID string
+	etag         string
+	data         []byte
+}
+
+// fileCache keeps recently downloaded submissions, bounded by total size and
+// evicting the least recently used. Entries are revalidated with
+// If-None-Match, so a stale one only costs a full download.
+type fileCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+func newFileCache(maxBytes int64) *fileCache {
+	return &fileCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *fileCache) get(submissionID string) (cachedFile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[submissionID]
+	if !ok {
+		return cachedFile{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(cachedFile), true
+}
+
+func (c *fileCache) put(submissionID, etag string, data []byte) {
+	if etag == "" || int64(len(data)) > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.removeLocked(submissionID)
+	c.entries[submissionID] = c.order.PushFront(cachedFile{submissionID: submissionID, etag: etag, data: data})
+	c.size += int64(len(data))
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		c.removeLocked(oldest.Value.(cachedFile).submissionID)
+	}
+}
+
+func (c *fileCache) remove(submissionID string) {
+	c.mu.Lock()
+	c.removeLocked(submissionID)
+	c.mu.Unlock()
+}
+
+func (c *fileCache) removeLocked(submissionID string) {
+	el, ok := c.entries[submissionID]
+	if !ok {
+		return
+	}
+	c.order.Remove(el)
+	delete(c.entries, submissionID)
+	c.size -= int64(len(el.Value.(cachedFile).data))
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,55 +17,94 @@ type SubmissionMeta struct {
 	AssignmentID string    `json:"assignment_id"`
 	AuthorID     string    `json:"author_id"`
 	CreatedAt    time.Time `json:"created_at"`
+	Status       string    `json:"status"`
+}
+
+// HasFile reports whether the submission's file can be downloaded. Older
+// filestorage versions do not send a status at all.
+func (m SubmissionMeta) HasFile() bool {
+	return m.Status == "" || m.Status == "active"
 }
 
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	cache      *fileCache
 }
 
 const listSubmissionsPath = "/submissions"
 const downloadPath = "/submissions/download"
+const listPageSize = 500
 
-func NewClient(baseURL string) *Client {
-	return &Client{
+// NewClient returns a filestorage client. Downloads are cached up to
+// cacheBytes in total and revalidated with conditional requests; zero
+// disables the cache.
+func NewClient(baseURL string, cacheBytes int64) *Client {
+	c := &Client{
 		baseURL:    strings.TrimRight(baseURL, "/"),
 		httpClient: &http.Client{Timeout: 30 * time.Second},
 	}
+	if cacheBytes > 0 {
+		c.cache = newFileCache(cacheBytes)
+	}
+	return c
 }
 
 func (c *Client) ListSubmissions(ctx context.Context, assignmentID string) ([]SubmissionMeta, error) {
+	var (
+		submissions []SubmissionMeta
+		cursor      string
+	)
+	for {
+		page, next, err := c.listSubmissionsPage(ctx, assignmentID, cursor)
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, page...)
+		if next == "" {
+			return submissions, nil
+		}
+		cursor = next
+	}
+}
+
+func (c *Client) listSubmissionsPage(ctx context.Context, assignmentID, cursor string) ([]SubmissionMeta, string, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	u.Path = listSubmissionsPath
 	q := u.Query()
 	q.Set("assignment_id", assignmentID)
+	q.Set("limit", strconv.Itoa(listPageSize))
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
 	u.RawQuery = q.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("list submissions: status %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("list submissions: status %d", resp.StatusCode)
 	}
 
 	var payload struct {
 		Submissions []SubmissionMeta `json:"submissions"`
+		NextCursor  string           `json:"next_cursor"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return payload.Submissions, nil
+	return payload.Submissions, payload.NextCursor, nil
 }
 
 func (c *Client) DownloadSubmission(ctx context.Context, submissionID string) ([]byte, error) {
@@ -82,13 +122,27 @@ func (c *Client) DownloadSubmission(ctx context.Context, submissionID string) ([
 		return nil, err
 	}
 
+	var cached cachedFile
+	var haveCached bool
+	if c.cache != nil {
+		if cached, haveCached = c.cache.get(submissionID); haveCached {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && haveCached {
+		return cached.data, nil
+	}
 	if resp.StatusCode != http.StatusOK {
+		if c.cache != nil {
+			c.cache.remove(submissionID)
+		}
 		return nil, fmt.Errorf("download submission: status %d", resp.StatusCode)
 	}
 
@@ -96,5 +150,8 @@ func (c *Client) DownloadSubmission(ctx context.Context, submissionID string) ([
 	if err != nil {
 		return nil, err
 	}
+	if c.cache != nil {
+		c.cache.put(submissionID, resp.Header.Get("ETag"), data)
+	}
 	return data, nil
 }
@@ -32,24 +32,152 @@ func (s *FileReportStore) Save(report domain.CheckReport) error {
 		return err
 	}
 
-	path := filepath.Join(workDir, fmt.Sprintf("%s.json", sanitize(report.SubmissionID)))
-	data, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
+	// A check that was already running when its submission got deleted must
+	// not bring the report back.
+	if _, deleted := deletionMarkerLocked(workDir, report.SubmissionID); deleted {
+		return nil
+	}
+	for i := range report.Matches {
+		marker, deleted := deletionMarkerLocked(workDir, report.Matches[i].OtherSubmissionID)
+		if !deleted {
+			continue
+		}
+		report.Matches[i].OtherDeleted = true
+		if marker == markerPurged {
+			report.Matches[i].OtherAuthorID = ""
+		}
+	}
+
+	if err := writeReportLocked(workDir, report); err != nil {
 		return err
 	}
 
-	if err := os.WriteFile(path, data, 0o644); err != nil {
+	return s.writeOverallLocked(workDir)
+}
+
+// Create stores a new report unless the submission already has one that did
+// not fail, or was deleted, and returns the report in place and whether it
+// is the new one. Check-and-store is atomic, so concurrent starts of one
+// check create it only once.
+func (s *FileReportStore) Create(report domain.CheckReport) (domain.CheckReport, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workDir := filepath.Join(s.root, sanitize(report.WorkID))
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return domain.CheckReport{}, false, err
+	}
+
+	if _, deleted := deletionMarkerLocked(workDir, report.SubmissionID); deleted {
+		return report, false, nil
+	}
+	existing, err := readReportLocked(workDir, report.SubmissionID)
+	if err == nil && existing.Status != domain.CheckStatusFailed {
+		return existing, false, nil
+	}
+	if err != nil && !errors.Is(err, ErrReportNotFound) {
+		return domain.CheckReport{}, false, err
+	}
+
+	if err := writeReportLocked(workDir, report); err != nil {
+		return domain.CheckReport{}, false, err
+	}
+	if err := s.writeOverallLocked(workDir); err != nil {
+		return domain.CheckReport{}, false, err
+	}
+	return report, true, nil
+}
+
+// DeleteSubmission prunes the submission's own report and marks matches
+// against it in the other reports of the work. It leaves a marker behind so
+// that later saves for the same submission are dropped as well.
+func (s *FileReportStore) DeleteSubmission(workID, submissionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workDir := filepath.Join(s.root, sanitize(workID))
+	if err := os.MkdirAll(workDir, 0o755); err != nil {
+		return err
+	}
+
+	if err := writeDeletionMarkerLocked(workDir, submissionID, markerDeleted); err != nil {
 		return err
 	}
 
+	target := filepath.Join(workDir, fmt.Sprintf("%s.json", sanitize(submissionID)))
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	reports, err := readReportsLocked(workDir)
+	if err != nil {
+		return err
+	}
+	for _, rep := range reports {
+		changed := false
+		for i := range rep.Matches {
+			if rep.Matches[i].OtherSubmissionID == submissionID && !rep.Matches[i].OtherDeleted {
+				rep.Matches[i].OtherDeleted = true
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := writeReportLocked(workDir, rep); err != nil {
+			return err
+		}
+	}
+
 	return s.writeOverallLocked(workDir)
 }
 
+// Pending returns the reports still waiting for a worker: checks that were
+// queued, or not yet handed to a worker, when the service stopped.
+func (s *FileReportStore) Pending() ([]domain.CheckReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	works, err := os.ReadDir(s.root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var pending []domain.CheckReport
+	for _, work := range works {
+		if !work.IsDir() {
+			continue
+		}
+		workDir := filepath.Join(s.root, work.Name())
+		reports, err := readReportsLocked(workDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, rep := range reports {
+			if rep.Status != domain.CheckStatusPending {
+				continue
+			}
+			if _, deleted := deletionMarkerLocked(workDir, rep.SubmissionID); deleted {
+				continue
+			}
+			pending = append(pending, rep)
+		}
+	}
+	return pending, nil
+}
+
 func (s *FileReportStore) LoadBySubmissionID(workID, submissionID string) (domain.CheckReport, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	target := filepath.Join(s.root, sanitize(workID), fmt.Sprintf("%s.json", sanitize(submissionID)))
+	return readReportLocked(filepath.Join(s.root, sanitize(workID)), submissionID)
+}
+
+func readReportLocked(workDir, submissionID string) (domain.CheckReport, error) {
+	target := filepath.Join(workDir, fmt.Sprintf("%s.json", sanitize(submissionID)))
 
 	data, err := os.ReadFile(target)
 	if err != nil {
@@ -97,11 +225,130 @@ func sanitize(s string) string {
 	return s
 }
 
-func (s *FileReportStore) writeOverallLocked(workDir string) error {
-	entries, err := os.ReadDir(workDir)
+// PurgeSubmission identifies one submission of a purged author.
+type PurgeSubmission struct {
+	WorkID       string
+	SubmissionID string
+}
+
+// PurgeAuthor erases an author from every work: their own reports are
+// removed, and matches against them in other reports lose other_author_id
+// and are marked deleted. Returns how many reports were removed and how many
+// were scrubbed.
+func (s *FileReportStore) PurgeAuthor(authorID string, submissions []PurgeSubmission) (int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := make(map[string]bool, len(submissions))
+	for _, sub := range submissions {
+		workDir := filepath.Join(s.root, sanitize(sub.WorkID))
+		if err := os.MkdirAll(workDir, 0o755); err != nil {
+			return 0, 0, err
+		}
+		if err := writeDeletionMarkerLocked(workDir, sub.SubmissionID, markerPurged); err != nil {
+			return 0, 0, err
+		}
+		purged[sub.SubmissionID] = true
+	}
+
+	works, err := os.ReadDir(s.root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+
+	removed, scrubbed := 0, 0
+	for _, work := range works {
+		if !work.IsDir() {
+			continue
+		}
+		workDir := filepath.Join(s.root, work.Name())
+		reports, err := readReportsLocked(workDir)
+		if err != nil {
+			return removed, scrubbed, err
+		}
+
+		changed := false
+		for _, rep := range reports {
+			if rep.AuthorID == authorID || purged[rep.SubmissionID] {
+				target := filepath.Join(workDir, fmt.Sprintf("%s.json", sanitize(rep.SubmissionID)))
+				if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return removed, scrubbed, err
+				}
+				removed++
+				changed = true
+				continue
+			}
+
+			touched := false
+			for i := range rep.Matches {
+				m := &rep.Matches[i]
+				if m.OtherAuthorID == authorID || purged[m.OtherSubmissionID] {
+					m.OtherAuthorID = ""
+					m.OtherDeleted = true
+					touched = true
+				}
+			}
+			if !touched {
+				continue
+			}
+			if err := writeReportLocked(workDir, rep); err != nil {
+				return removed, scrubbed, err
+			}
+			scrubbed++
+			changed = true
+		}
+
+		if changed {
+			if err := s.writeOverallLocked(workDir); err != nil {
+				return removed, scrubbed, err
+			}
+		}
+	}
+
+	return removed, scrubbed, nil
+}
+
+// Deletion markers are left next to the reports so that checks finishing
+// after a deletion do not resurrect data. A purge marker additionally means
+// the author must not be named in other reports.
+const (
+	markerDeleted = "deleted"
+	markerPurged  = "purged"
+)
+
+func writeDeletionMarkerLocked(workDir, submissionID, marker string) error {
+	path := filepath.Join(workDir, fmt.Sprintf("%s.deleted", sanitize(submissionID)))
+	if existing, ok := deletionMarkerLocked(workDir, submissionID); ok && existing == markerPurged {
+		return nil
+	}
+	return os.WriteFile(path, []byte(marker), 0o644)
+}
+
+func deletionMarkerLocked(workDir, submissionID string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(workDir, fmt.Sprintf("%s.deleted", sanitize(submissionID))))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+func writeReportLocked(workDir string, report domain.CheckReport) error {
+	path := filepath.Join(workDir, fmt.Sprintf("%s.json", sanitize(report.SubmissionID)))
+	data, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
 		return err
 	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func readReportsLocked(workDir string) ([]domain.CheckReport, error) {
+	entries, err := os.ReadDir(workDir)
+	if err != nil {
+		return nil, err
+	}
 	var reports []domain.CheckReport
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || entry.Name() == "overall.json" {
@@ -109,14 +356,22 @@ func (s *FileReportStore) writeOverallLocked(workDir string) error {
 		}
 		data, err := os.ReadFile(filepath.Join(workDir, entry.Name()))
 		if err != nil {
-			return err
+			return nil, err
 		}
 		var rep domain.CheckReport
 		if err := json.Unmarshal(data, &rep); err != nil {
-			return err
+			return nil, err
 		}
 		reports = append(reports, rep)
 	}
+	return reports, nil
+}
+
+func (s *FileReportStore) writeOverallLocked(workDir string) error {
+	reports, err := readReportsLocked(workDir)
+	if err != nil {
+		return err
+	}
 	data, err := json.MarshalIndent(map[string]any{
 		"reports": reports,
 	}, "", "  ")
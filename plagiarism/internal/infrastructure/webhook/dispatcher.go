@@ -0,0 +1,292 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"plagiarism/internal/domain"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	maxBackoff     = 10 * time.Minute
+)
+
+type Store interface {
+	Get(id string) (domain.Webhook, error)
+	Subscribers(workID string) ([]domain.Webhook, error)
+	SaveDelivery(domain.WebhookDelivery) error
+	Pending() ([]domain.WebhookDelivery, error)
+}
+
+// Payload is the JSON body of every delivery.
+type Payload struct {
+	ID        string              `json:"id"`
+	Event     string              `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	WorkID    string              `json:"work_id,omitempty"`
+	Report    *domain.CheckReport `json:"report,omitempty"`
+}
+
+// Dispatcher sends check results to the subscribed webhooks. Failed
+// deliveries are retried with exponential backoff up to maxAttempts; ones
+// still waiting at shutdown stay pending and are picked up by Resume.
+type Dispatcher struct {
+	store       Store
+	client      *http.Client
+	maxAttempts int
+	retryBase   time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher sends deliveries only where guard lets them. They go out
+// directly, bypassing any proxy, since through a proxy the guard would see
+// only the proxy's address.
+func NewDispatcher(store Store, maxAttempts int, retryBase time.Duration, guard *Guard) *Dispatcher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = guard.DialContext
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		store:       store,
+		client:      &http.Client{Timeout: requestTimeout, Transport: transport},
+		maxAttempts: maxAttempts,
+		retryBase:   retryBase,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Notify queues deliveries for a saved report; reports still pending are
+// of no interest to webhooks.
+func (d *Dispatcher) Notify(report domain.CheckReport) {
+	if report.Status != domain.CheckStatusDone && report.Status != domain.CheckStatusFailed {
+		return
+	}
+
+	hooks, err := d.store.Subscribers(report.WorkID)
+	if err != nil {
+		log.Printf("webhooks: list subscribers work=%s: %v", report.WorkID, err)
+		return
+	}
+
+	events := []string{domain.WebhookEventReportCompleted}
+	if report.Status == domain.CheckStatusDone && len(report.Matches) > 0 {
+		events = append(events, domain.WebhookEventMatchFound)
+	}
+	for _, hook := range hooks {
+		for _, event := range events {
+			if !hook.Wants(event) {
+				continue
+			}
+			delivery, err := newDelivery(hook.ID, event, report.WorkID, &report)
+			if err != nil {
+				log.Printf("webhooks: build %s for webhook=%s: %v", event, hook.ID, err)
+				continue
+			}
+			d.start(delivery)
+		}
+	}
+}
+
+// Test sends a webhook.test event once, without retries, and returns the
+// logged outcome.
+func (d *Dispatcher) Test(ctx context.Context, hook domain.Webhook) (domain.WebhookDelivery, error) {
+	delivery, err := newDelivery(hook.ID, domain.WebhookEventTest, hook.WorkID, nil)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	d.attempt(ctx, hook, &delivery)
+	if delivery.Status == domain.DeliveryStatusPending {
+		delivery.Status = domain.DeliveryStatusFailed
+	}
+	if err := d.store.SaveDelivery(delivery); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+// Resume restarts the deliveries that were pending when the service
+// stopped.
+func (d *Dispatcher) Resume() error {
+	pending, err := d.store.Pending()
+	if err != nil {
+		return err
+	}
+	for _, delivery := range pending {
+		d.wg.Add(1)
+		go d.run(delivery)
+	}
+	return nil
+}
+
+// Close stops retrying and waits for in-flight attempts.
+func (d *Dispatcher) Close() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) start(delivery domain.WebhookDelivery) {
+	if err := d.store.SaveDelivery(delivery); err != nil {
+		log.Printf("webhooks: save delivery=%s: %v", delivery.ID, err)
+	}
+	d.wg.Add(1)
+	go d.run(delivery)
+}
+
+func (d *Dispatcher) run(delivery domain.WebhookDelivery) {
+	defer d.wg.Done()
+
+	for {
+		if delivery.NextAttemptAt != nil {
+			timer := time.NewTimer(time.Until(*delivery.NextAttemptAt))
+			select {
+			case <-timer.C:
+			case <-d.ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+
+		// The webhook is read again before every attempt: it may have been
+		// deleted, or its URL changed, while the delivery waited.
+		hook, err := d.store.Get(delivery.WebhookID)
+		if err != nil {
+			return
+		}
+		retry := d.attempt(d.ctx, hook, &delivery)
+		if d.ctx.Err() != nil {
+			// Cut short by shutdown; the attempt does not count.
+			return
+		}
+		if retry && delivery.Attempts < d.maxAttempts {
+			next := time.Now().UTC().Add(d.backoff(delivery.Attempts))
+			delivery.NextAttemptAt = &next
+		} else {
+			delivery.NextAttemptAt = nil
+			if delivery.Status == domain.DeliveryStatusPending {
+				delivery.Status = domain.DeliveryStatusFailed
+			}
+		}
+		if err := d.store.SaveDelivery(delivery); err != nil {
+			log.Printf("webhooks: save delivery=%s: %v", delivery.ID, err)
+		}
+		if delivery.Status != domain.DeliveryStatusPending {
+			return
+		}
+	}
+}
+
+// attempt posts the delivery once and records the outcome in it. It reports
+// whether a failure is worth retrying.
+func (d *Dispatcher) attempt(ctx context.Context, hook domain.Webhook, delivery *domain.WebhookDelivery) bool {
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now().UTC()
+	delivery.LastStatusCode = 0
+	delivery.LastError = ""
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		delivery.LastError = err.Error()
+		return false
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "plagiarism-webhooks")
+	req.Header.Set("X-Webhook-Id", hook.ID)
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", Sign(hook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		delivery.LastError = err.Error()
+		return true
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.LastStatusCode = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		delivery.Status = domain.DeliveryStatusDelivered
+		return false
+	}
+	delivery.LastError = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	// Other 4xx mean the receiver rejects the payload itself; sending it
+	// again will not help.
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.retryBase
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
+
+// Sign returns the X-Webhook-Signature value: the hex HMAC-SHA256 of
+// "{timestamp}.{body}" under the webhook secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewID returns a random identifier for webhooks, deliveries and secrets.
+func NewID(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func newDelivery(webhookID, event, workID string, report *domain.CheckReport) (domain.WebhookDelivery, error) {
+	id, err := NewID(16)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	now := time.Now().UTC()
+	body, err := json.Marshal(Payload{
+		ID:        id,
+		Event:     event,
+		CreatedAt: now,
+		WorkID:    workID,
+		Report:    report,
+	})
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return domain.WebhookDelivery{
+		ID:        id,
+		WebhookID: webhookID,
+		Event:     event,
+		Status:    domain.DeliveryStatusPending,
+		Payload:   body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
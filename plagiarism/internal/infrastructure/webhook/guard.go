@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrForbiddenDestination means a webhook URL leads into the internal
+// network.
+var ErrForbiddenDestination = errors.New("webhook destination is not allowed")
+
+// Guard keeps webhooks away from loopback, private and link-local addresses,
+// so that subscribing cannot be used to reach services behind the firewall.
+// Hosts on the allowlist are exempt.
+type Guard struct {
+	allowed map[string]bool
+	dialer  *net.Dialer
+}
+
+func NewGuard(allowedHosts []string) *Guard {
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		allowed[strings.ToLower(host)] = true
+	}
+	return &Guard{
+		allowed: allowed,
+		dialer:  &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second},
+	}
+}
+
+// CheckURL rejects a URL whose host resolves to an internal address.
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if g.allowed[strings.ToLower(u.Hostname())] {
+		return nil
+	}
+	_, err = g.resolve(ctx, u.Hostname())
+	return err
+}
+
+// DialContext connects only to addresses that passed the check, so neither
+// redirects nor a DNS answer that changed since CheckURL get around it.
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if g.allowed[strings.ToLower(host)] {
+		return g.dialer.DialContext(ctx, network, address)
+	}
+
+	addrs, err := g.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := g.dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (g *Guard) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		addrs, err = net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, addr := range addrs {
+		if internalAddr(addr) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", ErrForbiddenDestination, host, addr)
+		}
+	}
+	return addrs, nil
+}
+
+func internalAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast()
+}
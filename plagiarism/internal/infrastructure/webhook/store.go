@@ -0,0 +1,357 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"plagiarism/internal/domain"
+)
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+const (
+	// deliveryLogSize is how many of the latest deliveries are kept per
+	// webhook. Pending ones are never dropped to make room.
+	deliveryLogSize = 100
+	// compactAfter is how many records a delivery log may grow to before it
+	// is rewritten with just the kept deliveries.
+	compactAfter = 4 * deliveryLogSize
+)
+
+type state struct {
+	Webhooks []domain.Webhook `json:"webhooks"`
+	// Deliveries is only read, from files written before the logs were split
+	// out.
+	Deliveries map[string][]domain.WebhookDelivery `json:"deliveries,omitempty"`
+}
+
+// FileStore keeps webhooks in a JSON file, rewritten when one is created or
+// deleted. Deliveries change far more often and carry the reports, so each
+// webhook has its own append-only log in deliveries/<id>.jsonl: a save
+// appends one record, and replaying the log keeps the latest record of every
+// delivery.
+type FileStore struct {
+	path        string
+	deliveryDir string
+
+	mu       sync.Mutex
+	webhooks []domain.Webhook
+	// deliveries are kept per webhook, oldest first.
+	deliveries map[string][]domain.WebhookDelivery
+	// records counts the lines in each delivery log.
+	records map[string]int
+}
+
+func NewFileStore(root string) (*FileStore, error) {
+	s := &FileStore{
+		path:        filepath.Join(root, "webhooks.json"),
+		deliveryDir: filepath.Join(root, "deliveries"),
+		deliveries:  make(map[string][]domain.WebhookDelivery),
+		records:     make(map[string]int),
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	s.webhooks = st.Webhooks
+
+	for _, hook := range s.webhooks {
+		log, err := s.readLog(hook.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(log) == 0 {
+			log = st.Deliveries[hook.ID]
+		}
+		// Rewriting drops superseded records and any line torn by a crash.
+		if err := s.compactLocked(hook.ID, log); err != nil {
+			return nil, err
+		}
+	}
+	if st.Deliveries != nil {
+		if err := s.writeLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *FileStore) Create(hook domain.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.webhooks = append(s.webhooks, hook)
+	if err := s.writeLocked(); err != nil {
+		s.webhooks = s.webhooks[:len(s.webhooks)-1]
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) List() ([]domain.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]domain.Webhook(nil), s.webhooks...), nil
+}
+
+func (s *FileStore) Get(id string) (domain.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, hook := range s.webhooks {
+		if hook.ID == id {
+			return hook, nil
+		}
+	}
+	return domain.Webhook{}, ErrWebhookNotFound
+}
+
+// Delete removes the webhook together with its delivery log.
+func (s *FileStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, hook := range s.webhooks {
+		if hook.ID != id {
+			continue
+		}
+		webhooks := append(s.webhooks[:i:i], s.webhooks[i+1:]...)
+		prev := s.webhooks
+		s.webhooks = webhooks
+		if err := s.writeLocked(); err != nil {
+			s.webhooks = prev
+			return err
+		}
+		delete(s.deliveries, id)
+		delete(s.records, id)
+		if err := os.Remove(s.logPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return ErrWebhookNotFound
+}
+
+// Subscribers returns the webhooks of workID and the global ones.
+func (s *FileStore) Subscribers(workID string) ([]domain.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var hooks []domain.Webhook
+	for _, hook := range s.webhooks {
+		if hook.WorkID == "" || hook.WorkID == workID {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+// SaveDelivery adds or updates a delivery in its webhook's log. Deliveries
+// of a deleted webhook are dropped.
+func (s *FileStore) SaveDelivery(delivery domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.existsLocked(delivery.WebhookID) {
+		return nil
+	}
+	if err := s.appendLocked(delivery); err != nil {
+		return err
+	}
+	log := upsertDelivery(s.deliveries[delivery.WebhookID], delivery)
+	s.deliveries[delivery.WebhookID] = log
+
+	if s.records[delivery.WebhookID] >= compactAfter {
+		return s.compactLocked(delivery.WebhookID, log)
+	}
+	return nil
+}
+
+// Deliveries returns the log of a webhook, newest first.
+func (s *FileStore) Deliveries(webhookID string) ([]domain.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.existsLocked(webhookID) {
+		return nil, ErrWebhookNotFound
+	}
+	log := s.deliveries[webhookID]
+	out := make([]domain.WebhookDelivery, 0, len(log))
+	for i := len(log) - 1; i >= 0; i-- {
+		out = append(out, log[i])
+	}
+	return out, nil
+}
+
+// Pending returns deliveries whose retries were cut short by a restart.
+func (s *FileStore) Pending() ([]domain.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []domain.WebhookDelivery
+	for _, log := range s.deliveries {
+		for _, delivery := range log {
+			if delivery.Status == domain.DeliveryStatusPending {
+				pending = append(pending, delivery)
+			}
+		}
+	}
+	return pending, nil
+}
+
+func (s *FileStore) existsLocked(id string) bool {
+	for _, hook := range s.webhooks {
+		if hook.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// upsertDelivery replaces the delivery with the same ID or appends it,
+// dropping the oldest finished ones beyond deliveryLogSize. Pending ones
+// are never dropped: Resume could not restart their retries.
+func upsertDelivery(log []domain.WebhookDelivery, delivery domain.WebhookDelivery) []domain.WebhookDelivery {
+	for i := range log {
+		if log[i].ID == delivery.ID {
+			log[i] = delivery
+			return log
+		}
+	}
+	log = append(log, delivery)
+
+	excess := len(log) - deliveryLogSize
+	if excess <= 0 {
+		return log
+	}
+	kept := log[:0]
+	for _, d := range log {
+		if excess > 0 && d.Status != domain.DeliveryStatusPending {
+			excess--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return kept
+}
+
+func (s *FileStore) logPath(webhookID string) string {
+	return filepath.Join(s.deliveryDir, webhookID+".jsonl")
+}
+
+// readLog replays a delivery log. Reading stops at the first record that
+// does not parse: only the last append can be torn.
+func (s *FileStore) readLog(webhookID string) ([]domain.WebhookDelivery, error) {
+	f, err := os.Open(s.logPath(webhookID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var log []domain.WebhookDelivery
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var delivery domain.WebhookDelivery
+			if json.Unmarshal(line, &delivery) != nil {
+				return log, nil
+			}
+			log = upsertDelivery(log, delivery)
+		}
+		if errors.Is(err, io.EOF) {
+			return log, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *FileStore) appendLocked(delivery domain.WebhookDelivery) error {
+	line, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.deliveryDir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.logPath(delivery.WebhookID), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.records[delivery.WebhookID]++
+	return nil
+}
+
+// compactLocked rewrites the log of a webhook with one record per kept
+// delivery.
+func (s *FileStore) compactLocked(webhookID string, log []domain.WebhookDelivery) error {
+	s.deliveries[webhookID] = log
+	if len(log) == 0 {
+		s.records[webhookID] = 0
+		if err := os.Remove(s.logPath(webhookID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, delivery := range log {
+		line, err := json.Marshal(delivery)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := writeFileAtomic(s.logPath(webhookID), buf.Bytes()); err != nil {
+		return err
+	}
+	s.records[webhookID] = len(log)
+	return nil
+}
+
+func (s *FileStore) writeLocked() error {
+	data, err := json.MarshalIndent(state{Webhooks: s.webhooks}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Secrets are stored here, so the file is private.
+	return writeFileAtomic(s.path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"plagiarism/internal/domain"
+)
+
+func TestFileStoreKeepsPendingDeliveriesBeyondLogSize(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewFileStore(root)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	if err := store.Create(domain.Webhook{ID: "hook", URL: "https://example.com/hook", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// A burst to a receiver that is down: the first deliveries wait for a
+	// retry while many more finish after them.
+	save := func(id string, status domain.DeliveryStatus) {
+		t.Helper()
+		err := store.SaveDelivery(domain.WebhookDelivery{ID: id, WebhookID: "hook", Status: status, CreatedAt: time.Now()})
+		if err != nil {
+			t.Fatalf("SaveDelivery: %v", err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		save(fmt.Sprintf("pending-%d", i), domain.DeliveryStatusPending)
+	}
+	for i := 0; i < compactAfter+deliveryLogSize; i++ {
+		save(fmt.Sprintf("failed-%d", i), domain.DeliveryStatusFailed)
+	}
+
+	// Reopening replays the log, as a restart does.
+	store, err = NewFileStore(root)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 3 {
+		t.Errorf("Pending() returned %d deliveries, want 3", len(pending))
+	}
+	log, err := store.Deliveries("hook")
+	if err != nil {
+		t.Fatalf("Deliveries: %v", err)
+	}
+	if len(log) != deliveryLogSize || log[len(log)-1].ID != "pending-0" {
+		t.Errorf("log has %d deliveries, oldest %s; want %d, oldest pending-0", len(log), log[len(log)-1].ID, deliveryLogSize)
+	}
+	if log[0].ID != fmt.Sprintf("failed-%d", compactAfter+deliveryLogSize-1) {
+		t.Errorf("newest delivery = %s", log[0].ID)
+	}
+
+	// Once finished, they are trimmed like the rest.
+	for i := 0; i < 3; i++ {
+		save(fmt.Sprintf("pending-%d", i), domain.DeliveryStatusDelivered)
+	}
+	save("last", domain.DeliveryStatusDelivered)
+	if log, _ = store.Deliveries("hook"); len(log) != deliveryLogSize || log[len(log)-1].ID != "pending-1" {
+		t.Errorf("log has %d deliveries, oldest %s; want %d, oldest pending-1", len(log), log[len(log)-1].ID, deliveryLogSize)
+	}
+}
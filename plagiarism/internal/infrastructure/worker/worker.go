@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"fmt"
+	"log"
 	"sync"
 
 	"plagiarism/internal/domain"
@@ -13,6 +14,20 @@ type Reporter interface {
 	Save(domain.CheckReport) error
 }
 
+// Notifier is told about every report the worker saves.
+type Notifier interface {
+	Notify(domain.CheckReport)
+}
+
+// Notifiers passes every report on to each of its notifiers in turn.
+type Notifiers []Notifier
+
+func (n Notifiers) Notify(report domain.CheckReport) {
+	for _, notifier := range n {
+		notifier.Notify(report)
+	}
+}
+
 type FilestorageClient interface {
 	ListSubmissions(ctx context.Context, assignmentID string) ([]filestorage.SubmissionMeta, error)
 	DownloadSubmission(ctx context.Context, submissionID string) ([]byte, error)
@@ -23,12 +38,18 @@ type Worker struct {
 	fs        FilestorageClient
 	threshold float64
 	onError   func(domain.CheckReport, error)
+	notifier  Notifier
 
 	tasks chan domain.CheckReport
 	wg    sync.WaitGroup
+
+	// Batch feeders send under sendMu's read lock and give up once quit is
+	// closed, so Close can safely close tasks after taking the write lock.
+	quit   chan struct{}
+	sendMu sync.RWMutex
 }
 
-func NewWorker(reporter Reporter, fs FilestorageClient, threshold float64, workers int, onError func(domain.CheckReport, error)) *Worker {
+func NewWorker(reporter Reporter, fs FilestorageClient, threshold float64, workers int, onError func(domain.CheckReport, error), notifier Notifier) *Worker {
 	if workers < 1 {
 		workers = 1
 	}
@@ -38,7 +59,9 @@ func NewWorker(reporter Reporter, fs FilestorageClient, threshold float64, worke
 		fs:        fs,
 		threshold: threshold,
 		onError:   onError,
+		notifier:  notifier,
 		tasks:     make(chan domain.CheckReport, 32),
+		quit:      make(chan struct{}),
 	}
 
 	w.wg.Add(workers)
@@ -49,7 +72,10 @@ func NewWorker(reporter Reporter, fs FilestorageClient, threshold float64, worke
 }
 
 func (w *Worker) Close() {
+	close(w.quit)
+	w.sendMu.Lock()
 	close(w.tasks)
+	w.sendMu.Unlock()
 	w.wg.Wait()
 }
 
@@ -66,6 +92,50 @@ func (w *Worker) Enqueue(_ context.Context, report domain.CheckReport) error {
 	}
 }
 
+// EnqueueBatch queues reports without the queue-size limit of Enqueue: they
+// are fed to the workers in the background as the queue drains. Reports
+// still waiting at shutdown stay pending, to be resumed on the next start.
+func (w *Worker) EnqueueBatch(_ context.Context, reports []domain.CheckReport) error {
+	if w.fs == nil {
+		return fmt.Errorf("filestorage client not configured")
+	}
+
+	go func() {
+		w.sendMu.RLock()
+		defer w.sendMu.RUnlock()
+		for i, report := range reports {
+			// Once quit is closed tasks may be too, and a select would
+			// pick between the two at random.
+			if w.stopping() {
+				w.leaveUnsent(reports[i:])
+				return
+			}
+			select {
+			case w.tasks <- report:
+			case <-w.quit:
+				w.leaveUnsent(reports[i:])
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *Worker) stopping() bool {
+	select {
+	case <-w.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// leaveUnsent keeps the reports pending: filestorage has already dropped the
+// event that asked for them, so only a resume after restart starts them.
+func (w *Worker) leaveUnsent(reports []domain.CheckReport) {
+	log.Printf("worker stopped with %d checks not started; they stay pending", len(reports))
+}
+
 func (w *Worker) loop() {
 	defer w.wg.Done()
 	for report := range w.tasks {
@@ -78,9 +148,19 @@ func (w *Worker) loop() {
 			report.Matches = matches
 			report.AuthorID = selfAuthor
 		}
-		if err := w.reporter.Save(report); err != nil && w.onError != nil {
+		w.save(report)
+	}
+}
+
+func (w *Worker) save(report domain.CheckReport) {
+	if err := w.reporter.Save(report); err != nil {
+		if w.onError != nil {
 			w.onError(report, err)
 		}
+		return
+	}
+	if w.notifier != nil {
+		w.notifier.Notify(report)
 	}
 }
 
@@ -105,7 +185,7 @@ func (w *Worker) compareWithWork(report domain.CheckReport) ([]domain.MatchResul
 	matches := make([]domain.MatchResult, 0, len(submissions))
 	selfAuthor := authors[report.SubmissionID]
 	for _, sub := range submissions {
-		if sub.SubmissionID == report.SubmissionID {
+		if sub.SubmissionID == report.SubmissionID || !sub.HasFile() {
 			continue
 		}
 
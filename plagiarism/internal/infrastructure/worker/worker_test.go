@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"plagiarism/internal/application/usecase"
+	"plagiarism/internal/domain"
+	"plagiarism/internal/infrastructure/filestorage"
+	"plagiarism/internal/infrastructure/report"
+)
+
+// fakeFilestorage serves one other submission; with release set, downloads
+// block until it is closed and started is closed on the first one.
+type fakeFilestorage struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (f *fakeFilestorage) ListSubmissions(context.Context, string) ([]filestorage.SubmissionMeta, error) {
+	return []filestorage.SubmissionMeta{{SubmissionID: "other", AuthorID: "bob"}}, nil
+}
+
+func (f *fakeFilestorage) DownloadSubmission(context.Context, string) ([]byte, error) {
+	if f.release != nil {
+		f.once.Do(func() { close(f.started) })
+		<-f.release
+	}
+	return []byte("print('hello')\n"), nil
+}
+
+type notifyFunc func(domain.CheckReport)
+
+func (f notifyFunc) Notify(report domain.CheckReport) { f(report) }
+
+func TestCloseMidBatchLeavesChecksToResume(t *testing.T) {
+	store := report.NewFileReportStore(t.TempDir())
+	reports := make([]domain.CheckReport, 100)
+	for i := range reports {
+		reports[i] = domain.CheckReport{
+			WorkID:       "hw1",
+			SubmissionID: fmt.Sprintf("s%03d", i),
+			Status:       domain.CheckStatusPending,
+			CreatedAt:    time.Now(),
+		}
+		if err := store.Save(reports[i]); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	// The first check hangs, so the batch fills the queue and waits.
+	fs := &fakeFilestorage{started: make(chan struct{}), release: make(chan struct{})}
+	w := NewWorker(store, fs, 0.8, 1, nil, nil)
+	if err := w.EnqueueBatch(context.Background(), reports); err != nil {
+		t.Fatalf("EnqueueBatch: %v", err)
+	}
+	<-fs.started
+
+	closed := make(chan struct{})
+	go func() {
+		w.Close()
+		close(closed)
+	}()
+	for !w.stopping() {
+		time.Sleep(time.Millisecond)
+	}
+	close(fs.release)
+	<-closed
+
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) == 0 {
+		t.Fatal("no checks left pending after closing mid-batch")
+	}
+	assertStatuses(t, store, len(reports)-len(pending), len(pending))
+
+	// The next start picks them up.
+	resumed := make(chan domain.CheckReport, len(reports))
+	w = NewWorker(store, &fakeFilestorage{}, 0.8, 1, nil, notifyFunc(func(r domain.CheckReport) { resumed <- r }))
+	defer w.Close()
+	n, err := usecase.NewCheckService(store, w, nil).ResumeChecks(context.Background())
+	if err != nil {
+		t.Fatalf("ResumeChecks: %v", err)
+	}
+	if n != len(pending) {
+		t.Fatalf("ResumeChecks() = %d, want %d", n, len(pending))
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case <-resumed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d of %d resumed checks finished", i, n)
+		}
+	}
+	assertStatuses(t, store, len(reports), 0)
+}
+
+func assertStatuses(t *testing.T, store *report.FileReportStore, wantDone, wantPending int) {
+	t.Helper()
+
+	all, err := store.GetOverallByWork("hw1")
+	if err != nil {
+		t.Fatalf("GetOverallByWork: %v", err)
+	}
+	counts := make(map[domain.CheckStatus]int)
+	for _, r := range all {
+		counts[r.Status]++
+	}
+	if counts[domain.CheckStatusDone] != wantDone || counts[domain.CheckStatusPending] != wantPending || len(all) != wantDone+wantPending {
+		t.Errorf("statuses = %v, want %d done and %d pending", counts, wantDone, wantPending)
+	}
+}
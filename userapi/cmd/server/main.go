@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,29 +13,60 @@ import (
 
 	"userapi/internal/api/http/router"
 	"userapi/internal/application/usecase"
+	"userapi/internal/infrastructure/auth"
 	"userapi/internal/infrastructure/config"
 	"userapi/internal/infrastructure/filestorage"
+	"userapi/internal/infrastructure/httpclient"
 	"userapi/internal/infrastructure/plagiarism"
 	"userapi/internal/infrastructure/wordcloud"
 )
 
 func main() {
-	fsClient := filestorage.NewClient(config.FilestorageURL())
-	plagClient := plagiarism.NewService(plagiarism.NewClient(config.PlagiarismURL()))
+	downstream := config.LoadDownstreamConfig()
+	fsClient := filestorage.NewClient(config.FilestorageURL(), httpclient.New("filestorage", downstream))
+	plagClient := plagiarism.NewService(plagiarism.NewClient(config.PlagiarismURL(), httpclient.New("plagiarism", downstream)))
 
-	submitUseCase := usecase.NewSubmitUseCase(fsClient, plagClient)
+	submitUseCase := usecase.NewSubmitUseCase(fsClient)
 	reportsUseCase := usecase.NewReportsUseCase(plagClient)
-	wcClient := wordcloud.NewClient(config.WordcloudServiceURL())
+	submissionsUseCase := usecase.NewSubmissionsUseCase(fsClient)
+	wcClient := wordcloud.NewClient(config.WordcloudServiceURL(), httpclient.New("wordcloud", downstream))
 	wordcloudUseCase := usecase.NewWordcloudUseCase(wcClient)
+	eventsUseCase := usecase.NewEventsUseCase(plagClient, fsClient)
 
-	r := router.NewRouter(submitUseCase, reportsUseCase, wordcloudUseCase)
+	authConfig, err := config.LoadAuthConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	authenticator, err := newAuthenticator(authConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var membership *auth.Membership
+	if authConfig.MembershipFile != "" {
+		membership, err = auth.LoadMembership(authConfig.MembershipFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	rateLimits, err := config.LoadRateLimitConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r := router.NewRouter(submitUseCase, reportsUseCase, submissionsUseCase, wordcloudUseCase, eventsUseCase, authenticator, membership, rateLimits, config.RequestTimeout())
 	handler := r.SetupRoutes()
 
 	port := ":" + config.ServerPort()
+	// Cancelled on shutdown to end the event streams, which never go idle
+	// on their own.
+	baseCtx, cancelStreams := context.WithCancel(context.Background())
 	srv := &http.Server{
-		Addr:    port,
-		Handler: handler,
+		Addr:        port,
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
 	}
+	srv.RegisterOnShutdown(cancelStreams)
 
 	go func() {
 		log.Printf("userapi gateway starting on %s", port)
@@ -56,3 +89,33 @@ func main() {
 
 	log.Println("userapi gateway stopped")
 }
+
+func newAuthenticator(authConfig *config.AuthConfig) (*auth.Authenticator, error) {
+	var rsaKeys map[string]*rsa.PublicKey
+	if authConfig.JWKSFile != "" {
+		var err error
+		rsaKeys, err = auth.LoadJWKS(authConfig.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make(map[string]auth.APIKey, len(authConfig.APIKeys)+1)
+	for key, subject := range authConfig.APIKeys {
+		keys[key] = auth.APIKey{Subject: subject}
+	}
+	// The instructor token keeps working as an API key that is instructor
+	// in every course.
+	if token := config.InstructorToken(); token != "" {
+		if _, ok := keys[token]; !ok {
+			keys[token] = auth.APIKey{Subject: "instructor", Role: auth.RoleInstructor}
+		}
+	}
+
+	verifier := auth.NewJWTVerifier([]byte(authConfig.JWTHMACSecret), rsaKeys, authConfig.JWTIssuer, authConfig.JWTAudience)
+	apiKeys := auth.NewAPIKeys(keys)
+	if !verifier.Enabled() && !apiKeys.Enabled() {
+		log.Println("no JWT keys or API keys configured: every request to /works, /submissions and /wordcloud will get 401")
+	}
+	return auth.NewAuthenticator(verifier, apiKeys), nil
+}
@@ -38,8 +38,20 @@ func mapAPIError(err error) apiError {
 		return apiError{status: http.StatusBadRequest, code: code, message: message}
 	case apperr.CodeNotFound:
 		return apiError{status: http.StatusNotFound, code: code, message: message}
+	case apperr.CodeUnauthorized:
+		return apiError{status: http.StatusUnauthorized, code: code, message: message}
+	case apperr.CodeForbidden:
+		return apiError{status: http.StatusForbidden, code: code, message: message}
 	case apperr.CodeDownstream:
 		return apiError{status: http.StatusBadGateway, code: code, message: message}
+	case apperr.CodeQuotaExceeded:
+		return apiError{status: http.StatusTooManyRequests, code: code, message: message}
+	case apperr.CodeStorageQuotaExceeded:
+		return apiError{status: http.StatusRequestEntityTooLarge, code: code, message: message}
+	case apperr.CodeConflict:
+		return apiError{status: http.StatusConflict, code: code, message: message}
+	case apperr.CodeRateLimited:
+		return apiError{status: http.StatusTooManyRequests, code: code, message: message}
 	default:
 		return apiError{status: http.StatusInternalServerError, code: apperr.CodeInternal, message: defaultMessageForCode(apperr.CodeInternal)}
 	}
@@ -51,8 +63,20 @@ func defaultMessageForCode(code apperr.Code) string {
 		return "validation error"
 	case apperr.CodeNotFound:
 		return "resource not found"
+	case apperr.CodeUnauthorized:
+		return "authentication required"
+	case apperr.CodeForbidden:
+		return "forbidden"
 	case apperr.CodeDownstream:
 		return "downstream error"
+	case apperr.CodeQuotaExceeded:
+		return "submission quota exceeded"
+	case apperr.CodeStorageQuotaExceeded:
+		return "storage quota exceeded"
+	case apperr.CodeConflict:
+		return "conflict"
+	case apperr.CodeRateLimited:
+		return "too many requests"
 	default:
 		return "internal error"
 	}
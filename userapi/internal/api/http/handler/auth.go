@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"userapi/internal/application/dto"
+	"userapi/internal/infrastructure/auth"
+)
+
+// RequireAuth answers 401 to requests without valid credentials and passes
+// the caller's principal on in the request context. A principal already
+// resolved by the rate limiter is reused.
+func RequireAuth(authn *auth.Authenticator, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.PrincipalFrom(r.Context()); ok {
+			next(w, r)
+			return
+		}
+		principal, err := authn.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="userapi"`)
+			respondError(w, err)
+			return
+		}
+		next(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	}
+}
+
+// principalSubject returns the authenticated login; routes behind
+// RequireAuth always have one.
+func principalSubject(r *http.Request) string {
+	principal, _ := auth.PrincipalFrom(r.Context())
+	return principal.Subject
+}
+
+// viewerFor shapes responses about a work: staff of its course see
+// everything, everyone else only their own data.
+func viewerFor(r *http.Request, membership *auth.Membership, workID string) dto.Viewer {
+	principal, _ := auth.PrincipalFrom(r.Context())
+	return dto.Viewer{
+		Login: principal.Subject,
+		Staff: membership.RoleIn(principal, workID).IsStaff(),
+	}
+}
+
+func isInstructor(r *http.Request, membership *auth.Membership, workID string) bool {
+	principal, _ := auth.PrincipalFrom(r.Context())
+	return membership.RoleIn(principal, workID) == auth.RoleInstructor
+}
+
+// canSeeSubmission allows a submission's author and the staff of its course.
+func canSeeSubmission(r *http.Request, membership *auth.Membership, ref *dto.SubmissionRef) bool {
+	viewer := viewerFor(r, membership, ref.WorkID)
+	return viewer.Staff || ref.AuthorID == viewer.Login
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"userapi/internal/application/usecase"
+	apperr "userapi/internal/common/errors"
+	"userapi/internal/infrastructure/auth"
+)
+
+type DeleteSubmissionHandler struct {
+	useCase    *usecase.SubmissionsUseCase
+	membership *auth.Membership
+}
+
+func NewDeleteSubmissionHandler(uc *usecase.SubmissionsUseCase, membership *auth.Membership) *DeleteSubmissionHandler {
+	return &DeleteSubmissionHandler{useCase: uc, membership: membership}
+}
+
+func (h *DeleteSubmissionHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondMethodNotAllowed(w, "only GET and DELETE are allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/works/")
+	workID, submissionID, ok := strings.Cut(path, "/submissions/")
+	if !ok || workID == "" || submissionID == "" || strings.Contains(submissionID, "/") {
+		respondValidationError(w, "expected /works/{work_id}/submissions/{submission_id}")
+		return
+	}
+
+	if !isInstructor(r, h.membership, workID) {
+		respondError(w, apperr.New(apperr.CodeForbidden, "instructor role required"))
+		return
+	}
+
+	if err := h.useCase.Delete(r.Context(), workID, submissionID); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"userapi/internal/application/usecase"
+	apperr "userapi/internal/common/errors"
+	"userapi/internal/infrastructure/auth"
+)
+
+type DownloadSubmissionHandler struct {
+	useCase    *usecase.SubmissionsUseCase
+	membership *auth.Membership
+}
+
+func NewDownloadSubmissionHandler(uc *usecase.SubmissionsUseCase, membership *auth.Membership) *DownloadSubmissionHandler {
+	return &DownloadSubmissionHandler{useCase: uc, membership: membership}
+}
+
+func (h *DownloadSubmissionHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET is allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/submissions/")
+	submissionID, ok := strings.CutSuffix(path, "/download")
+	if !ok || submissionID == "" || strings.Contains(submissionID, "/") {
+		respondValidationError(w, "expected /submissions/{submission_id}/download")
+		return
+	}
+
+	ref, err := h.useCase.Locate(r.Context(), submissionID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	if !canSeeSubmission(r, h.membership, ref) {
+		respondError(w, apperr.New(apperr.CodeForbidden, "submission belongs to another author"))
+		return
+	}
+
+	download, err := h.useCase.Download(r.Context(), submissionID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	// Presigned URLs expire, so neither the redirect nor the proxied file
+	// may be cached.
+	w.Header().Set("Cache-Control", "no-store")
+	if download.RedirectURL != "" {
+		http.Redirect(w, r, download.RedirectURL, http.StatusFound)
+		return
+	}
+	defer download.Body.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, download.Filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, download.Body); err != nil {
+		log.Printf("download: submission_id=%s stream failed: %v", submissionID, err)
+	}
+}
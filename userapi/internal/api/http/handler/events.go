@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"userapi/internal/application/usecase"
+	"userapi/internal/infrastructure/auth"
+)
+
+// heartbeatInterval keeps idle streams from being cut by proxies.
+const heartbeatInterval = 15 * time.Second
+
+// retryMillis tells EventSource clients how soon to reconnect when the
+// stream ends, e.g. when plagiarism restarts.
+const retryMillis = 3000
+
+type EventsHandler struct {
+	useCase    *usecase.EventsUseCase
+	membership *auth.Membership
+}
+
+func NewEventsHandler(uc *usecase.EventsUseCase, membership *auth.Membership) *EventsHandler {
+	return &EventsHandler{useCase: uc, membership: membership}
+}
+
+func (h *EventsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET is allowed")
+		return
+	}
+
+	workID, ok := extractWorkID(r.URL.Path, "/events")
+	if !ok {
+		respondValidationError(w, "expected /works/{work_id}/events")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondValidationError(w, "streaming is not supported")
+		return
+	}
+
+	events, err := h.useCase.Watch(r.Context(), workID, viewerFor(r, h.membership, workID))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Status, data)
+		}
+		flusher.Flush()
+	}
+}
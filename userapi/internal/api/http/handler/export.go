@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"userapi/internal/application/usecase"
+	apperr "userapi/internal/common/errors"
+	"userapi/internal/infrastructure/auth"
+)
+
+type ExportHandler struct {
+	useCase    *usecase.SubmissionsUseCase
+	membership *auth.Membership
+}
+
+func NewExportHandler(uc *usecase.SubmissionsUseCase, membership *auth.Membership) *ExportHandler {
+	return &ExportHandler{useCase: uc, membership: membership}
+}
+
+func (h *ExportHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET is allowed")
+		return
+	}
+
+	workID, ok := extractWorkID(r.URL.Path, "/export")
+	if !ok {
+		respondValidationError(w, "expected /works/{work_id}/export")
+		return
+	}
+
+	if !isInstructor(r, h.membership, workID) {
+		respondError(w, apperr.New(apperr.CodeForbidden, "instructor role required"))
+		return
+	}
+
+	includeReports := false
+	if v := r.URL.Query().Get("include_reports"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondValidationError(w, "include_reports must be a boolean")
+			return
+		}
+		includeReports = parsed
+	}
+
+	body, err := h.useCase.Export(r.Context(), workID, includeReports)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": workID + ".zip"}))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		// filestorage cut the archive short; pass the truncation on rather
+		// than end the response as if it were complete.
+		log.Printf("export: work_id=%s stream failed: %v", workID, err)
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"userapi/internal/application/usecase"
+)
+
+type QuotaHandler struct {
+	useCase *usecase.SubmissionsUseCase
+}
+
+func NewQuotaHandler(uc *usecase.SubmissionsUseCase) *QuotaHandler {
+	return &QuotaHandler{useCase: uc}
+}
+
+func (h *QuotaHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET is allowed")
+		return
+	}
+
+	workID, ok := extractWorkID(r.URL.Path, "/quota")
+	if !ok {
+		respondValidationError(w, "expected /works/{work_id}/quota")
+		return
+	}
+
+	resp, err := h.useCase.Quota(r.Context(), workID, principalSubject(r))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
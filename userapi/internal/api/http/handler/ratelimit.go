@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apperr "userapi/internal/common/errors"
+	"userapi/internal/infrastructure/auth"
+	"userapi/internal/infrastructure/config"
+	"userapi/internal/infrastructure/ratelimit"
+)
+
+// RateLimiter gives every caller a token bucket per route. Authenticated
+// callers are keyed by subject, everyone else by client address, so bad
+// credentials are limited too.
+type RateLimiter struct {
+	authn             *auth.Authenticator
+	limiters          map[string]*ratelimit.Limiter
+	trustForwardedFor bool
+}
+
+func NewRateLimiter(authn *auth.Authenticator, cfg *config.RateLimitConfig) *RateLimiter {
+	limiters := make(map[string]*ratelimit.Limiter)
+	for _, route := range config.RateLimitRoutes {
+		if limit := cfg.For(route); limit.Enabled() {
+			limiters[route] = ratelimit.NewLimiter(limit.Requests, limit.Per, limit.Burst)
+		}
+	}
+	return &RateLimiter{authn: authn, limiters: limiters, trustForwardedFor: cfg.TrustForwardedFor}
+}
+
+// Limit answers 429 with Retry-After once the caller has used up the
+// bucket of the route the request maps to.
+func (l *RateLimiter) Limit(route func(*http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limiter, ok := l.limiters[route(r)]
+		if !ok {
+			next(w, r)
+			return
+		}
+
+		var key string
+		if principal, err := l.authn.Authenticate(r); err == nil {
+			key = "user:" + principal.Subject
+			r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+		} else {
+			key = "ip:" + l.clientIP(r)
+		}
+
+		if allowed, wait := limiter.Allow(key); !allowed {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			respondError(w, apperr.New(apperr.CodeRateLimited, fmt.Sprintf("too many requests, retry in %ds", seconds)))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// FixedRoute maps every request of a handler to one route.
+func FixedRoute(route string) func(*http.Request) string {
+	return func(*http.Request) string { return route }
+}
+
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.trustForwardedFor {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
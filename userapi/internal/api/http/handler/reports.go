@@ -5,14 +5,16 @@ import (
 	"net/http"
 
 	"userapi/internal/application/usecase"
+	"userapi/internal/infrastructure/auth"
 )
 
 type ReportsHandler struct {
-	useCase *usecase.ReportsUseCase
+	useCase    *usecase.ReportsUseCase
+	membership *auth.Membership
 }
 
-func NewReportsHandler(uc *usecase.ReportsUseCase) *ReportsHandler {
-	return &ReportsHandler{useCase: uc}
+func NewReportsHandler(uc *usecase.ReportsUseCase, membership *auth.Membership) *ReportsHandler {
+	return &ReportsHandler{useCase: uc, membership: membership}
 }
 
 func (h *ReportsHandler) Handle(w http.ResponseWriter, r *http.Request) {
@@ -27,7 +29,7 @@ func (h *ReportsHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.useCase.GetByWork(r.Context(), workID)
+	resp, err := h.useCase.GetByWork(r.Context(), workID, viewerFor(r, h.membership, workID))
 	if err != nil {
 		respondError(w, err)
 		return
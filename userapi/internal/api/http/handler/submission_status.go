@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"userapi/internal/application/usecase"
+	apperr "userapi/internal/common/errors"
+	"userapi/internal/infrastructure/auth"
+)
+
+type SubmissionStatusHandler struct {
+	reports     *usecase.ReportsUseCase
+	submissions *usecase.SubmissionsUseCase
+	membership  *auth.Membership
+}
+
+func NewSubmissionStatusHandler(reportsUC *usecase.ReportsUseCase, submissionsUC *usecase.SubmissionsUseCase, membership *auth.Membership) *SubmissionStatusHandler {
+	return &SubmissionStatusHandler{reports: reportsUC, submissions: submissionsUC, membership: membership}
+}
+
+func (h *SubmissionStatusHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET is allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/works/")
+	workID, submissionID, ok := strings.Cut(path, "/submissions/")
+	if !ok || workID == "" || submissionID == "" || strings.Contains(submissionID, "/") {
+		respondValidationError(w, "expected /works/{work_id}/submissions/{submission_id}")
+		return
+	}
+
+	ref, err := h.submissions.Locate(r.Context(), submissionID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	if ref.WorkID != workID {
+		respondError(w, apperr.New(apperr.CodeNotFound, "submission not found"))
+		return
+	}
+	if !canSeeSubmission(r, h.membership, ref) {
+		respondError(w, apperr.New(apperr.CodeForbidden, "submission belongs to another author"))
+		return
+	}
+
+	resp, err := h.reports.GetBySubmission(r.Context(), ref, viewerFor(r, h.membership, workID))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"userapi/internal/application/usecase"
+	"userapi/internal/infrastructure/auth"
+)
+
+type SubmissionsHandler struct {
+	useCase    *usecase.SubmissionsUseCase
+	membership *auth.Membership
+}
+
+func NewSubmissionsHandler(uc *usecase.SubmissionsUseCase, membership *auth.Membership) *SubmissionsHandler {
+	return &SubmissionsHandler{useCase: uc, membership: membership}
+}
+
+func (h *SubmissionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w, "only GET is allowed")
+		return
+	}
+
+	workID, ok := extractWorkID(r.URL.Path, "/submissions")
+	if !ok {
+		respondValidationError(w, "expected /works/{work_id}/submissions")
+		return
+	}
+
+	resp, err := h.useCase.ListByWork(r.Context(), workID, viewerFor(r, h.membership, workID))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
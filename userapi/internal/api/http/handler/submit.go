@@ -1,11 +1,11 @@
 package handler
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 
 	"userapi/internal/application/dto"
@@ -35,6 +35,9 @@ func (h *SubmitHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// filestorage validates the key and its 400 is passed through as is.
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+
 	mr, err := r.MultipartReader()
 	if err != nil {
 		respondValidationError(w, "expected multipart form data")
@@ -43,12 +46,7 @@ func (h *SubmitHandler) Handle(w http.ResponseWriter, r *http.Request) {
 
 	maxUploadSize := config.MaxUploadSize()
 
-	var (
-		login       string
-		fileData    []byte
-		filename    string
-		contentType string
-	)
+	login := principalSubject(r)
 
 	for {
 		part, err := mr.NextPart()
@@ -61,77 +59,90 @@ func (h *SubmitHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		}
 
 		switch part.FormName() {
-		case "login":
-			body, readErr := io.ReadAll(part)
-			_ = part.Close()
-			if readErr != nil {
-				respondValidationError(w, "failed to read login")
-				return
-			}
-			login = string(body)
 		case "file":
-			data, ct, readErr := readFilePart(part, maxUploadSize)
-			if readErr != nil {
-				if errors.Is(readErr, errFileTooLarge) {
+			// The file is forwarded to filestorage as it is read.
+			defer part.Close()
+
+			file := newSizeLimitedReader(part, maxUploadSize)
+			buffered := bufio.NewReader(file)
+			if _, peekErr := buffered.Peek(1); peekErr != nil {
+				if file.Exceeded() {
 					respondValidationError(w, fmt.Sprintf("file exceeds max size %d bytes", maxUploadSize))
 					return
 				}
+				if peekErr == io.EOF {
+					respondValidationError(w, "file is required")
+					return
+				}
 				respondValidationError(w, "failed to read file")
 				return
 			}
-			fileData = data
-			filename = part.FileName()
-			contentType = ct
-		default:
-			_ = part.Close()
-		}
-	}
 
-	if login == "" {
-		respondValidationError(w, "login is required")
-		return
-	}
+			contentType := part.Header.Get("Content-Type")
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
 
-	if len(fileData) == 0 {
-		respondValidationError(w, "file is required")
-		return
-	}
+			req := dto.SubmitWorkRequest{
+				WorkID:      workID,
+				Login:       login,
+				File:        buffered,
+				Filename:    part.FileName(),
+				ContentType: contentType,
+				// Filestorage scopes the key to the author, so one
+				// caller's key never replays another's upload.
+				IdempotencyKey: idempotencyKey,
+			}
 
-	if contentType == "" {
-		contentType = "application/octet-stream"
-	}
+			resp, err := h.useCase.Submit(r.Context(), req)
+			if err != nil {
+				if file.Exceeded() {
+					respondValidationError(w, fmt.Sprintf("file exceeds max size %d bytes", maxUploadSize))
+					return
+				}
+				respondError(w, err)
+				return
+			}
 
-	req := dto.SubmitWorkRequest{
-		WorkID:      workID,
-		Login:       login,
-		Data:        fileData,
-		Filename:    filename,
-		ContentType: contentType,
+			w.Header().Set("Content-Type", "application/json")
+			if resp.Replayed {
+				w.Header().Set("Idempotent-Replayed", "true")
+			}
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(resp)
+			return
+		default:
+			_ = part.Close()
+		}
 	}
 
-	resp, err := h.useCase.Submit(r.Context(), req)
-	if err != nil {
-		respondError(w, err)
-		return
-	}
+	respondValidationError(w, "file is required")
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	_ = json.NewEncoder(w).Encode(resp)
+type sizeLimitedReader struct {
+	r        io.Reader
+	max      int64
+	read     int64
+	exceeded bool
 }
 
-func readFilePart(part *multipart.Part, maxUploadSize int64) ([]byte, string, error) {
-	defer part.Close()
+func newSizeLimitedReader(r io.Reader, max int64) *sizeLimitedReader {
+	return &sizeLimitedReader{r: r, max: max}
+}
 
-	limited := io.LimitReader(part, maxUploadSize+1)
-	data, err := io.ReadAll(limited)
-	if err != nil {
-		return nil, "", err
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, errFileTooLarge
 	}
-
-	if int64(len(data)) > maxUploadSize {
-		return nil, "", errFileTooLarge
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		l.exceeded = true
+		return n, errFileTooLarge
 	}
+	return n, err
+}
 
-	return data, part.Header.Get("Content-Type"), nil
+func (l *sizeLimitedReader) Exceeded() bool {
+	return l.exceeded
 }
@@ -4,14 +4,18 @@ import (
 	"net/http"
 
 	"userapi/internal/application/usecase"
+	apperr "userapi/internal/common/errors"
+	"userapi/internal/infrastructure/auth"
 )
 
 type WordcloudHandler struct {
-	useCase *usecase.WordcloudUseCase
+	useCase     *usecase.WordcloudUseCase
+	submissions *usecase.SubmissionsUseCase
+	membership  *auth.Membership
 }
 
-func NewWordcloudHandler(uc *usecase.WordcloudUseCase) *WordcloudHandler {
-	return &WordcloudHandler{useCase: uc}
+func NewWordcloudHandler(uc *usecase.WordcloudUseCase, submissions *usecase.SubmissionsUseCase, membership *auth.Membership) *WordcloudHandler {
+	return &WordcloudHandler{useCase: uc, submissions: submissions, membership: membership}
 }
 
 func (h *WordcloudHandler) Handle(w http.ResponseWriter, r *http.Request) {
@@ -26,6 +30,16 @@ func (h *WordcloudHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ref, err := h.submissions.Locate(r.Context(), submissionID)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+	if !canSeeSubmission(r, h.membership, ref) {
+		respondError(w, apperr.New(apperr.CodeForbidden, "submission belongs to another author"))
+		return
+	}
+
 	img, err := h.useCase.Generate(r.Context(), submissionID)
 	if err != nil {
 		respondError(w, err)
@@ -1,32 +1,57 @@
 package router
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"userapi/internal/api/http/handler"
 	"userapi/internal/application/usecase"
+	"userapi/internal/infrastructure/auth"
+	"userapi/internal/infrastructure/config"
 )
 
 type Router struct {
-	submitHandler    *handler.SubmitHandler
-	reportsHandler   *handler.ReportsHandler
-	wordcloudHandler *handler.WordcloudHandler
+	submitHandler      *handler.SubmitHandler
+	reportsHandler     *handler.ReportsHandler
+	submissionsHandler *handler.SubmissionsHandler
+	deleteHandler      *handler.DeleteSubmissionHandler
+	statusHandler      *handler.SubmissionStatusHandler
+	downloadHandler    *handler.DownloadSubmissionHandler
+	quotaHandler       *handler.QuotaHandler
+	exportHandler      *handler.ExportHandler
+	wordcloudHandler   *handler.WordcloudHandler
+	eventsHandler      *handler.EventsHandler
+	authenticator      *auth.Authenticator
+	rateLimiter        *handler.RateLimiter
+	requestTimeout     time.Duration
 }
 
-func NewRouter(submitUC *usecase.SubmitUseCase, reportsUC *usecase.ReportsUseCase, wcUC *usecase.WordcloudUseCase) *Router {
+func NewRouter(submitUC *usecase.SubmitUseCase, reportsUC *usecase.ReportsUseCase, submissionsUC *usecase.SubmissionsUseCase, wcUC *usecase.WordcloudUseCase, eventsUC *usecase.EventsUseCase, authenticator *auth.Authenticator, membership *auth.Membership, rateLimits *config.RateLimitConfig, requestTimeout time.Duration) *Router {
 	return &Router{
-		submitHandler:    handler.NewSubmitHandler(submitUC),
-		reportsHandler:   handler.NewReportsHandler(reportsUC),
-		wordcloudHandler: handler.NewWordcloudHandler(wcUC),
+		submitHandler:      handler.NewSubmitHandler(submitUC),
+		reportsHandler:     handler.NewReportsHandler(reportsUC, membership),
+		submissionsHandler: handler.NewSubmissionsHandler(submissionsUC, membership),
+		deleteHandler:      handler.NewDeleteSubmissionHandler(submissionsUC, membership),
+		statusHandler:      handler.NewSubmissionStatusHandler(reportsUC, submissionsUC, membership),
+		downloadHandler:    handler.NewDownloadSubmissionHandler(submissionsUC, membership),
+		quotaHandler:       handler.NewQuotaHandler(submissionsUC),
+		exportHandler:      handler.NewExportHandler(submissionsUC, membership),
+		wordcloudHandler:   handler.NewWordcloudHandler(wcUC, submissionsUC, membership),
+		eventsHandler:      handler.NewEventsHandler(eventsUC, membership),
+		authenticator:      authenticator,
+		rateLimiter:        handler.NewRateLimiter(authenticator, rateLimits),
+		requestTimeout:     requestTimeout,
 	}
 }
 
 func (r *Router) SetupRoutes() http.Handler {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/works/", r.handleWorks)
-	mux.HandleFunc("/wordcloud", r.wordcloudHandler.Handle)
+	mux.HandleFunc("/works/", r.protect(worksRoute, r.handleWorks))
+	mux.HandleFunc("/submissions/", r.protect(handler.FixedRoute(config.RouteDownload), r.downloadHandler.Handle))
+	mux.HandleFunc("/wordcloud", r.protect(handler.FixedRoute(config.RouteWordcloud), r.wordcloudHandler.Handle))
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
@@ -35,11 +60,48 @@ func (r *Router) SetupRoutes() http.Handler {
 	return corsMiddleware(mux)
 }
 
+// protect rate-limits a handler, requires authentication and bounds the
+// request's lifetime.
+func (r *Router) protect(route func(*http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return r.rateLimiter.Limit(route, handler.RequireAuth(r.authenticator, r.withDeadline(route, next)))
+}
+
+// withDeadline gives a request, and every downstream call made for it, the
+// request timeout. Event streams and exports last as long as the client
+// stays.
+func (r *Router) withDeadline(route func(*http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch route(req) {
+		case config.RouteEvents, config.RouteExport:
+			next(w, req)
+			return
+		}
+		ctx, cancel := context.WithTimeout(req.Context(), r.requestTimeout)
+		defer cancel()
+		next(w, req.WithContext(ctx))
+	}
+}
+
+// worksRoute names the rate limit a /works/ request counts against.
+func worksRoute(req *http.Request) string {
+	switch path := req.URL.Path; {
+	case strings.HasSuffix(path, "/submit"):
+		return config.RouteSubmit
+	case strings.HasSuffix(path, "/export"):
+		return config.RouteExport
+	case strings.HasSuffix(path, "/events"):
+		return config.RouteEvents
+	default:
+		return config.RouteDefault
+	}
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, Idempotency-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "Idempotent-Replayed, Retry-After")
 
 		if req.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -58,6 +120,18 @@ func (r *Router) handleWorks(w http.ResponseWriter, req *http.Request) {
 		r.submitHandler.Handle(w, req)
 	case strings.HasSuffix(path, "/reports"):
 		r.reportsHandler.Handle(w, req)
+	case strings.HasSuffix(path, "/export"):
+		r.exportHandler.Handle(w, req)
+	case strings.HasSuffix(path, "/quota"):
+		r.quotaHandler.Handle(w, req)
+	case strings.HasSuffix(path, "/events"):
+		r.eventsHandler.Handle(w, req)
+	case strings.HasSuffix(path, "/submissions"):
+		r.submissionsHandler.Handle(w, req)
+	case strings.Contains(path, "/submissions/") && req.Method == http.MethodGet:
+		r.statusHandler.Handle(w, req)
+	case strings.Contains(path, "/submissions/"):
+		r.deleteHandler.Handle(w, req)
 	default:
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusNotFound)
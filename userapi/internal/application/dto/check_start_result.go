@@ -1,6 +0,0 @@
-package dto
-
-type CheckStartResult struct {
-	SubmissionID string `json:"submission_id"`
-	Status       string `json:"status"`
-}
@@ -0,0 +1,14 @@
+package dto
+
+// Quota is an author's usage against filestorage's limits. A zero limit
+// means unlimited, and the matching remaining field is then omitted.
+type Quota struct {
+	WorkID               string `json:"work_id"`
+	Login                string `json:"login"`
+	SubmissionsUsed      int64  `json:"submissions_used"`
+	SubmissionsLimit     int64  `json:"submissions_limit"`
+	SubmissionsRemaining *int64 `json:"submissions_remaining,omitempty"`
+	BytesUsed            int64  `json:"bytes_used"`
+	BytesLimit           int64  `json:"bytes_limit"`
+	BytesRemaining       *int64 `json:"bytes_remaining,omitempty"`
+}
@@ -3,7 +3,7 @@ package dto
 import "time"
 
 type MatchResult struct {
-	OtherSubmissionID string  `json:"other_submission_id"`
+	OtherSubmissionID string  `json:"other_submission_id,omitempty"`
 	OtherAuthorID     string  `json:"other_author_id,omitempty"`
 	Equal             bool    `json:"equal"`
 	MatchedBytes      int64   `json:"matched_bytes"`
@@ -11,6 +11,7 @@ type MatchResult struct {
 	Similarity        float64 `json:"similarity"`
 	SelfSize          int64   `json:"self_size"`
 	OtherSize         int64   `json:"other_size"`
+	OtherDeleted      bool    `json:"other_deleted,omitempty"`
 }
 
 type CheckReport struct {
@@ -27,3 +28,24 @@ type WorkReportsResponse struct {
 	WorkID  string        `json:"work_id"`
 	Reports []CheckReport `json:"reports"`
 }
+
+// SubmissionCheck is the check state of one submission. Status is the
+// report's status, "pending" while plagiarism has not picked the submission
+// up yet, or "quarantined" for files that are never checked.
+type SubmissionCheck struct {
+	WorkID       string       `json:"work_id"`
+	SubmissionID string       `json:"submission_id"`
+	Status       string       `json:"status"`
+	Report       *CheckReport `json:"report,omitempty"`
+}
+
+// CheckEvent is a status change of a submission's check, pushed by
+// GET /works/{work_id}/events.
+type CheckEvent struct {
+	WorkID       string    `json:"work_id"`
+	SubmissionID string    `json:"submission_id"`
+	AuthorID     string    `json:"author_id,omitempty"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	At           time.Time `json:"at"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+type Submission struct {
+	SubmissionID string    `json:"submission_id"`
+	AuthorID     string    `json:"author_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Status       string    `json:"status,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	// OriginalFilename is empty for submissions stored before it was kept.
+	OriginalFilename string `json:"original_filename,omitempty"`
+}
+
+// SubmissionRef is where a submission belongs, for access checks, and its
+// filestorage status.
+type SubmissionRef struct {
+	SubmissionID string
+	WorkID       string
+	AuthorID     string
+	Status       string
+}
+
+type WorkSubmissionsResponse struct {
+	WorkID      string       `json:"work_id"`
+	Submissions []Submission `json:"submissions"`
+}
+
+// SubmissionDownload is either a RedirectURL to the object store or, when
+// filestorage cannot presign, the file Body to proxy.
+type SubmissionDownload struct {
+	RedirectURL string
+	Body        io.ReadCloser
+	Filename    string
+}
@@ -1,14 +1,21 @@
 package dto
 
+import "io"
+
 type SubmitWorkRequest struct {
 	WorkID      string
 	Login       string
-	Data        []byte
+	File        io.Reader
 	Filename    string
 	ContentType string
+	// IdempotencyKey is passed on to filestorage, which remembers it.
+	IdempotencyKey string
 }
 
 type SubmitWorkResponse struct {
 	SubmissionID string `json:"submission_id"`
 	CheckStatus  string `json:"check_status"`
+	// Replayed is set when filestorage answered a repeated Idempotency-Key
+	// with the original upload.
+	Replayed bool `json:"-"`
 }
@@ -0,0 +1,8 @@
+package dto
+
+// Viewer is the caller a response is shaped for. Students (Staff false) get
+// only their own submissions and reports, without other authors' identities.
+type Viewer struct {
+	Login string
+	Staff bool
+}
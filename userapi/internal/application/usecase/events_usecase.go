@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+
+	"userapi/internal/application/dto"
+	apperr "userapi/internal/common/errors"
+)
+
+type EventsProvider interface {
+	WatchEvents(ctx context.Context, workID string) (<-chan dto.CheckEvent, error)
+}
+
+type SubmissionLocator interface {
+	GetSubmission(ctx context.Context, submissionID string) (*dto.SubmissionRef, error)
+}
+
+type EventsUseCase struct {
+	provider EventsProvider
+	locator  SubmissionLocator
+}
+
+func NewEventsUseCase(provider EventsProvider, locator SubmissionLocator) *EventsUseCase {
+	return &EventsUseCase{provider: provider, locator: locator}
+}
+
+// Watch streams the check status changes of a work; students get only the
+// events of their own submissions. The channel is closed when ctx ends or
+// plagiarism drops the stream.
+func (uc *EventsUseCase) Watch(ctx context.Context, workID string, viewer dto.Viewer) (<-chan dto.CheckEvent, error) {
+	upstream, err := uc.provider.WatchEvents(ctx, workID)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "watch events failed")
+	}
+	if viewer.Staff {
+		return upstream, nil
+	}
+
+	events := make(chan dto.CheckEvent)
+	go func() {
+		defer close(events)
+		// A pending event comes before the worker has looked the author
+		// up, so ownership is resolved through filestorage and remembered.
+		authors := make(map[string]string)
+		for event := range upstream {
+			author := event.AuthorID
+			if author == "" {
+				author = uc.authorOf(ctx, authors, event.SubmissionID)
+			}
+			if author != viewer.Login {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (uc *EventsUseCase) authorOf(ctx context.Context, authors map[string]string, submissionID string) string {
+	if author, ok := authors[submissionID]; ok {
+		return author
+	}
+	ref, err := uc.locator.GetSubmission(ctx, submissionID)
+	if err != nil {
+		return ""
+	}
+	authors[submissionID] = ref.AuthorID
+	return ref.AuthorID
+}
@@ -11,8 +11,11 @@ import (
 
 type ReportsProvider interface {
 	GetReports(ctx context.Context, workID string) (*dto.WorkReportsResponse, error)
+	GetCheck(ctx context.Context, workID, submissionID string) (*dto.CheckReport, error)
 }
 
+const submissionStatusQuarantined = "quarantined"
+
 type ReportsUseCase struct {
 	provider ReportsProvider
 }
@@ -21,7 +24,7 @@ func NewReportsUseCase(provider ReportsProvider) *ReportsUseCase {
 	return &ReportsUseCase{provider: provider}
 }
 
-func (uc *ReportsUseCase) GetByWork(ctx context.Context, workID string) (*dto.WorkReportsResponse, error) {
+func (uc *ReportsUseCase) GetByWork(ctx context.Context, workID string, viewer dto.Viewer) (*dto.WorkReportsResponse, error) {
 	resp, err := uc.provider.GetReports(ctx, workID)
 	if err != nil {
 		if errors.Is(err, plagclient.ErrNotFound) {
@@ -29,5 +32,62 @@ func (uc *ReportsUseCase) GetByWork(ctx context.Context, workID string) (*dto.Wo
 		}
 		return nil, apperr.Wrap(err, apperr.CodeDownstream, "get reports failed")
 	}
-	return resp, nil
+	if viewer.Staff {
+		return resp, nil
+	}
+
+	own := make([]dto.CheckReport, 0, 1)
+	for _, report := range resp.Reports {
+		if report.AuthorID == viewer.Login {
+			own = append(own, redactReport(report))
+		}
+	}
+	if len(own) == 0 {
+		return nil, apperr.New(apperr.CodeNotFound, "report not found")
+	}
+	return &dto.WorkReportsResponse{WorkID: resp.WorkID, Reports: own}, nil
+}
+
+// GetBySubmission returns the check of a submission the caller may see; ref
+// comes from SubmissionsUseCase.Locate.
+func (uc *ReportsUseCase) GetBySubmission(ctx context.Context, ref *dto.SubmissionRef, viewer dto.Viewer) (*dto.SubmissionCheck, error) {
+	check := &dto.SubmissionCheck{
+		WorkID:       ref.WorkID,
+		SubmissionID: ref.SubmissionID,
+		Status:       checkStatusPending,
+	}
+	if ref.Status == submissionStatusQuarantined {
+		check.Status = submissionStatusQuarantined
+		return check, nil
+	}
+
+	report, err := uc.provider.GetCheck(ctx, ref.WorkID, ref.SubmissionID)
+	if err != nil {
+		// filestorage delivers checks through its outbox, so a stored
+		// submission without a report is still on its way.
+		if errors.Is(err, plagclient.ErrNotFound) {
+			return check, nil
+		}
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "get check failed")
+	}
+	if !viewer.Staff {
+		redacted := redactReport(*report)
+		report = &redacted
+	}
+	check.Status = report.Status
+	check.Report = report
+	return check, nil
+}
+
+// redactReport keeps how much of a student's work matched but not whose
+// submission it matched.
+func redactReport(report dto.CheckReport) dto.CheckReport {
+	matches := make([]dto.MatchResult, len(report.Matches))
+	for i, match := range report.Matches {
+		match.OtherSubmissionID = ""
+		match.OtherAuthorID = ""
+		matches[i] = match
+	}
+	report.Matches = matches
+	return report
 }
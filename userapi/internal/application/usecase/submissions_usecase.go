@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"userapi/internal/application/dto"
+	apperr "userapi/internal/common/errors"
+	fsclient "userapi/internal/infrastructure/filestorage"
+)
+
+type SubmissionsProvider interface {
+	ListSubmissions(ctx context.Context, assignmentID string) ([]dto.Submission, error)
+	GetSubmission(ctx context.Context, submissionID string) (*dto.SubmissionRef, error)
+	DeleteSubmission(ctx context.Context, assignmentID, submissionID string) error
+	DownloadURL(ctx context.Context, submissionID string) (string, error)
+	DownloadSubmission(ctx context.Context, submissionID string) (io.ReadCloser, error)
+	GetQuota(ctx context.Context, assignmentID, login string) (*dto.Quota, error)
+	ExportAssignment(ctx context.Context, assignmentID string, includeReports bool) (io.ReadCloser, error)
+}
+
+type SubmissionsUseCase struct {
+	provider SubmissionsProvider
+}
+
+func NewSubmissionsUseCase(provider SubmissionsProvider) *SubmissionsUseCase {
+	return &SubmissionsUseCase{provider: provider}
+}
+
+func (uc *SubmissionsUseCase) ListByWork(ctx context.Context, workID string, viewer dto.Viewer) (*dto.WorkSubmissionsResponse, error) {
+	submissions, err := uc.provider.ListSubmissions(ctx, workID)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "list submissions failed")
+	}
+	if !viewer.Staff {
+		own := make([]dto.Submission, 0)
+		for _, submission := range submissions {
+			if submission.AuthorID == viewer.Login {
+				own = append(own, submission)
+			}
+		}
+		submissions = own
+	}
+	return &dto.WorkSubmissionsResponse{
+		WorkID:      workID,
+		Submissions: submissions,
+	}, nil
+}
+
+// Locate finds the work and author of a submission so the caller's access
+// can be checked before anything else is fetched.
+func (uc *SubmissionsUseCase) Locate(ctx context.Context, submissionID string) (*dto.SubmissionRef, error) {
+	ref, err := uc.provider.GetSubmission(ctx, submissionID)
+	if err != nil {
+		if errors.Is(err, fsclient.ErrNotFound) {
+			return nil, apperr.New(apperr.CodeNotFound, "submission not found")
+		}
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "get submission failed")
+	}
+	return ref, nil
+}
+
+func (uc *SubmissionsUseCase) Delete(ctx context.Context, workID, submissionID string) error {
+	if err := uc.provider.DeleteSubmission(ctx, workID, submissionID); err != nil {
+		if errors.Is(err, fsclient.ErrNotFound) {
+			return apperr.New(apperr.CodeNotFound, "submission not found")
+		}
+		return apperr.Wrap(err, apperr.CodeDownstream, "delete submission failed")
+	}
+	return nil
+}
+
+// Download prefers a presigned URL so the bytes bypass both gateways, and
+// falls back to streaming through filestorage.
+func (uc *SubmissionsUseCase) Download(ctx context.Context, submissionID string) (*dto.SubmissionDownload, error) {
+	url, err := uc.provider.DownloadURL(ctx, submissionID)
+	switch {
+	case err == nil:
+		return &dto.SubmissionDownload{RedirectURL: url, Filename: submissionID}, nil
+	case errors.Is(err, fsclient.ErrNotFound):
+		return nil, apperr.New(apperr.CodeNotFound, "submission not found")
+	case errors.Is(err, fsclient.ErrQuarantined):
+		return nil, apperr.New(apperr.CodeForbidden, "submission is quarantined")
+	case !errors.Is(err, fsclient.ErrPresignUnavailable):
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "get download url failed")
+	}
+
+	body, err := uc.provider.DownloadSubmission(ctx, submissionID)
+	if err != nil {
+		if errors.Is(err, fsclient.ErrNotFound) {
+			return nil, apperr.New(apperr.CodeNotFound, "submission not found")
+		}
+		if errors.Is(err, fsclient.ErrQuarantined) {
+			return nil, apperr.New(apperr.CodeForbidden, "submission is quarantined")
+		}
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "download submission failed")
+	}
+	return &dto.SubmissionDownload{Body: body, Filename: submissionID}, nil
+}
+
+func (uc *SubmissionsUseCase) Quota(ctx context.Context, workID, login string) (*dto.Quota, error) {
+	if login == "" {
+		return nil, apperr.New(apperr.CodeValidation, "login is required")
+	}
+	quota, err := uc.provider.GetQuota(ctx, workID, login)
+	if err != nil {
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "get quota failed")
+	}
+	return quota, nil
+}
+
+// Export streams the work's zip archive from filestorage.
+func (uc *SubmissionsUseCase) Export(ctx context.Context, workID string, includeReports bool) (io.ReadCloser, error) {
+	body, err := uc.provider.ExportAssignment(ctx, workID, includeReports)
+	if err != nil {
+		if errors.Is(err, fsclient.ErrNotFound) {
+			return nil, apperr.New(apperr.CodeNotFound, "work has no submissions")
+		}
+		if errors.Is(err, fsclient.ErrReportsUnavailable) {
+			return nil, apperr.Wrap(err, apperr.CodeDownstream, "plagiarism reports are not available")
+		}
+		return nil, apperr.Wrap(err, apperr.CodeDownstream, "export work failed")
+	}
+	return body, nil
+}
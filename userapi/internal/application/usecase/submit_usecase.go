@@ -2,44 +2,56 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net/http"
 
 	"userapi/internal/application/dto"
 	apperr "userapi/internal/common/errors"
+	fsclient "userapi/internal/infrastructure/filestorage"
 )
 
 type FilestorageUploader interface {
-	UploadSubmission(ctx context.Context, assignmentID, login string, data []byte, filename, contentType string) (string, error)
+	UploadSubmission(ctx context.Context, assignmentID, login, idempotencyKey string, file io.Reader, filename, contentType string) (submissionID string, replayed bool, err error)
 }
 
-type PlagiarismStarter interface {
-	StartCheck(ctx context.Context, submissionID, workID string) (dto.CheckStartResult, error)
-}
+// checkStatusPending is what a new submission's check reports: filestorage
+// queues the check together with the upload and delivers it on its own.
+const checkStatusPending = "pending"
 
 type SubmitUseCase struct {
-	fs   FilestorageUploader
-	plag PlagiarismStarter
+	fs FilestorageUploader
 }
 
-func NewSubmitUseCase(fs FilestorageUploader, plag PlagiarismStarter) *SubmitUseCase {
-	return &SubmitUseCase{
-		fs:   fs,
-		plag: plag,
-	}
+func NewSubmitUseCase(fs FilestorageUploader) *SubmitUseCase {
+	return &SubmitUseCase{fs: fs}
 }
 
 func (uc *SubmitUseCase) Submit(ctx context.Context, req dto.SubmitWorkRequest) (*dto.SubmitWorkResponse, error) {
-	submissionID, err := uc.fs.UploadSubmission(ctx, req.WorkID, req.Login, req.Data, req.Filename, req.ContentType)
+	submissionID, replayed, err := uc.fs.UploadSubmission(ctx, req.WorkID, req.Login, req.IdempotencyKey, req.File, req.Filename, req.ContentType)
 	if err != nil {
+		var apiErr *fsclient.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.Status {
+			case http.StatusBadRequest:
+				return nil, apperr.Wrap(err, apperr.CodeValidation, apiErr.Message)
+			case http.StatusTooManyRequests:
+				return nil, apperr.Wrap(err, apperr.CodeQuotaExceeded, apiErr.Message)
+			case http.StatusRequestEntityTooLarge:
+				return nil, apperr.Wrap(err, apperr.CodeStorageQuotaExceeded, apiErr.Message)
+			case http.StatusConflict:
+				return nil, apperr.Wrap(err, apperr.CodeConflict, apiErr.Message)
+			}
+		}
+		if errors.Is(err, fsclient.ErrQuarantined) {
+			return nil, apperr.Wrap(err, apperr.CodeValidation, "file was flagged by the malware scanner and quarantined")
+		}
 		return nil, apperr.Wrap(err, apperr.CodeDownstream, "upload submission failed")
 	}
 
-	check, err := uc.plag.StartCheck(ctx, submissionID, req.WorkID)
-	if err != nil {
-		return nil, apperr.Wrap(err, apperr.CodeDownstream, "start plagiarism check failed")
-	}
-
 	return &dto.SubmitWorkResponse{
-		SubmissionID: check.SubmissionID,
-		CheckStatus:  check.Status,
+		SubmissionID: submissionID,
+		CheckStatus:  checkStatusPending,
+		Replayed:     replayed,
 	}, nil
 }
@@ -7,10 +7,20 @@ import (
 type Code string
 
 const (
-	CodeValidation Code = "validation_error"
-	CodeNotFound   Code = "not_found"
-	CodeDownstream Code = "downstream_error"
-	CodeInternal   Code = "internal_error"
+	CodeValidation   Code = "validation_error"
+	CodeNotFound     Code = "not_found"
+	CodeUnauthorized Code = "unauthorized"
+	CodeForbidden    Code = "forbidden"
+	CodeDownstream   Code = "downstream_error"
+	CodeInternal     Code = "internal_error"
+	// CodeQuotaExceeded and CodeStorageQuotaExceeded pass filestorage's
+	// quota rejections through to the client.
+	CodeQuotaExceeded        Code = "quota_exceeded"
+	CodeStorageQuotaExceeded Code = "storage_quota_exceeded"
+	// CodeConflict passes filestorage's Idempotency-Key conflicts through.
+	CodeConflict Code = "conflict"
+	// CodeRateLimited is the gateway's own per-caller request limit.
+	CodeRateLimited Code = "rate_limited"
 )
 
 type Error struct {
@@ -0,0 +1,36 @@
+package auth
+
+import "crypto/sha256"
+
+// APIKey is who a static key authenticates as. Role is optional; without it
+// the membership table decides.
+type APIKey struct {
+	Subject string
+	Role    Role
+}
+
+// APIKeys maps static keys for automation to their owners. Only hashes are
+// kept, so lookups do not leak key bytes through timing.
+type APIKeys struct {
+	owners map[[sha256.Size]byte]APIKey
+}
+
+func NewAPIKeys(keys map[string]APIKey) *APIKeys {
+	owners := make(map[[sha256.Size]byte]APIKey, len(keys))
+	for key, owner := range keys {
+		owners[sha256.Sum256([]byte(key))] = owner
+	}
+	return &APIKeys{owners: owners}
+}
+
+func (k *APIKeys) Lookup(key string) (APIKey, bool) {
+	if k == nil || key == "" {
+		return APIKey{}, false
+	}
+	owner, ok := k.owners[sha256.Sum256([]byte(key))]
+	return owner, ok
+}
+
+func (k *APIKeys) Enabled() bool {
+	return k != nil && len(k.owners) > 0
+}
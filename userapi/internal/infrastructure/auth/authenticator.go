@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	apperr "userapi/internal/common/errors"
+)
+
+// Authenticator resolves the caller of a request from an API key
+// (X-API-Key or a bearer token that is not a JWT) or a signed JWT bearer
+// token.
+type Authenticator struct {
+	jwt     *JWTVerifier
+	apiKeys *APIKeys
+}
+
+func NewAuthenticator(jwt *JWTVerifier, apiKeys *APIKeys) *Authenticator {
+	return &Authenticator{jwt: jwt, apiKeys: apiKeys}
+}
+
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, error) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return a.authenticateAPIKey(key)
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		return Principal{}, apperr.New(apperr.CodeUnauthorized, "authentication required")
+	}
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return Principal{}, apperr.New(apperr.CodeUnauthorized, "expected Authorization: Bearer <token>")
+	}
+
+	if strings.Count(token, ".") != 2 {
+		return a.authenticateAPIKey(token)
+	}
+	if !a.jwt.Enabled() {
+		return Principal{}, apperr.New(apperr.CodeUnauthorized, "bearer tokens are not accepted")
+	}
+	claims, err := a.jwt.Verify(token)
+	if err != nil {
+		return Principal{}, apperr.New(apperr.CodeUnauthorized, "invalid token: "+err.Error())
+	}
+	return principalFromClaims(claims)
+}
+
+func principalFromClaims(claims *Claims) (Principal, error) {
+	p := Principal{Subject: claims.Subject, Method: MethodJWT}
+	if claims.Role != "" {
+		role, err := ParseRole(claims.Role)
+		if err != nil {
+			return Principal{}, apperr.New(apperr.CodeUnauthorized, "invalid token: "+err.Error())
+		}
+		p.Role = role
+	}
+	if len(claims.Courses) > 0 {
+		p.Courses = make(map[string]Role, len(claims.Courses))
+		for course, name := range claims.Courses {
+			role, err := ParseRole(name)
+			if err != nil {
+				return Principal{}, apperr.New(apperr.CodeUnauthorized, "invalid token: "+err.Error())
+			}
+			p.Courses[course] = role
+		}
+	}
+	return p, nil
+}
+
+func (a *Authenticator) authenticateAPIKey(key string) (Principal, error) {
+	owner, ok := a.apiKeys.Lookup(key)
+	if !ok {
+		return Principal{}, apperr.New(apperr.CodeUnauthorized, "invalid api key")
+	}
+	return Principal{Subject: owner.Subject, Method: MethodAPIKey, Role: owner.Role}, nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func jwkOf(t *testing.T, kid string, bits int) jwk {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return jwk{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func writeJWKS(t *testing.T, keys ...jwk) string {
+	t.Helper()
+	data, err := json.Marshal(jwks{Keys: keys})
+	if err != nil {
+		t.Fatalf("encode jwks: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+	return path
+}
+
+func TestLoadJWKS(t *testing.T) {
+	strong := jwkOf(t, "k1", 2048)
+	weak := jwkOf(t, "weak", 1024)
+	encryption := jwkOf(t, "enc", 2048)
+	encryption.Use = "enc"
+
+	tests := []struct {
+		name     string
+		keys     []jwk
+		wantKids []string
+		wantErr  string
+	}{
+		{name: "signing key", keys: []jwk{strong}, wantKids: []string{"k1"}},
+		{name: "encryption key is skipped", keys: []jwk{strong, encryption}, wantKids: []string{"k1"}},
+		{name: "short RSA key", keys: []jwk{strong, weak}, wantErr: "1024-bit RSA key is too short"},
+		{name: "duplicate kid", keys: []jwk{strong, strong}, wantErr: "duplicate kid"},
+		{name: "no signing keys", keys: []jwk{encryption}, wantErr: "no RSA signing keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := LoadJWKS(writeJWKS(t, tt.keys...))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadJWKS() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadJWKS() error = %v", err)
+			}
+			if len(keys) != len(tt.wantKids) {
+				t.Fatalf("LoadJWKS() returned %d keys, want %d", len(keys), len(tt.wantKids))
+			}
+			for _, kid := range tt.wantKids {
+				if keys[kid] == nil {
+					t.Errorf("key %q is missing", kid)
+				}
+			}
+		})
+	}
+}
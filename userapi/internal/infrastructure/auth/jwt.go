@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far exp and nbf may be off before a token is rejected.
+const clockSkew = 30 * time.Second
+
+var (
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrTokenNotYetValid = errors.New("token not valid yet")
+	ErrInvalidClaims    = errors.New("invalid claims")
+)
+
+// Claims are the registered JWT claims the gateway checks plus the role
+// claims: role applies in every course, courses maps course id to role.
+type Claims struct {
+	Subject   string            `json:"sub"`
+	Issuer    string            `json:"iss"`
+	Audience  audience          `json:"aud"`
+	ExpiresAt *float64          `json:"exp"`
+	NotBefore *float64          `json:"nbf"`
+	Role      string            `json:"role"`
+	Courses   map[string]string `json:"courses"`
+}
+
+// audience accepts both forms RFC 7519 allows: a string or an array.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// JWTVerifier checks HS* tokens against a shared secret and RS* tokens
+// against RSA public keys by kid. The algorithm family is bound to the key
+// type, so an RSA public key can never be used as an HMAC secret.
+type JWTVerifier struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	issuer     string
+	audience   string
+	now        func() time.Time
+}
+
+// NewJWTVerifier returns a verifier; an empty issuer or audience is not
+// checked.
+func NewJWTVerifier(hmacSecret []byte, rsaKeys map[string]*rsa.PublicKey, issuer, audience string) *JWTVerifier {
+	return &JWTVerifier{
+		hmacSecret: hmacSecret,
+		rsaKeys:    rsaKeys,
+		issuer:     issuer,
+		audience:   audience,
+		now:        time.Now,
+	}
+}
+
+// Enabled reports whether any signing key is configured.
+func (v *JWTVerifier) Enabled() bool {
+	return v != nil && (len(v.hmacSecret) > 0 || len(v.rsaKeys) > 0)
+}
+
+// Verify checks the signature and claims of a compact JWS token.
+func (v *JWTVerifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if err := v.verifySignature(header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if err := v.validate(&claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (v *JWTVerifier) verifySignature(header jwtHeader, signed string, signature []byte) error {
+	switch header.Alg {
+	case "HS256", "HS384", "HS512":
+		if len(v.hmacSecret) == 0 {
+			return ErrUnsupportedAlg
+		}
+		mac := hmac.New(hashFor(header.Alg), v.hmacSecret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case "RS256", "RS384", "RS512":
+		key, err := v.rsaKey(header.Kid)
+		if err != nil {
+			return err
+		}
+		h := hashFor(header.Alg)()
+		h.Write([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(key, cryptoHash(header.Alg), h.Sum(nil), signature); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	default:
+		return ErrUnsupportedAlg
+	}
+}
+
+// rsaKey picks the key by kid; a token without kid is accepted only when
+// there is exactly one key.
+func (v *JWTVerifier) rsaKey(kid string) (*rsa.PublicKey, error) {
+	if kid == "" && len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key, nil
+		}
+	}
+	key, ok := v.rsaKeys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (v *JWTVerifier) validate(c *Claims) error {
+	now := v.now()
+	if c.Subject == "" {
+		return fmt.Errorf("%w: sub is required", ErrInvalidClaims)
+	}
+	if c.ExpiresAt == nil {
+		return fmt.Errorf("%w: exp is required", ErrInvalidClaims)
+	}
+	if now.Add(-clockSkew).After(unixTime(*c.ExpiresAt)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != nil && now.Add(clockSkew).Before(unixTime(*c.NotBefore)) {
+		return ErrTokenNotYetValid
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected iss", ErrInvalidClaims)
+	}
+	if v.audience != "" && !slices.Contains(c.Audience, v.audience) {
+		return fmt.Errorf("%w: unexpected aud", ErrInvalidClaims)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func hashFor(alg string) func() hash.Hash {
+	switch alg[2:] {
+	case "384":
+		return sha512.New384
+	case "512":
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
+
+func cryptoHash(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func signHS256(t *testing.T, header, claims map[string]any, secret []byte) string {
+	t.Helper()
+	signed := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, header, claims map[string]any, key *rsa.PrivateKey) string {
+	t.Helper()
+	signed := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// tamper flips a bit in the last byte of the signature.
+func tamper(token string) string {
+	i := strings.LastIndex(token, ".")
+	signature, _ := base64.RawURLEncoding.DecodeString(token[i+1:])
+	signature[len(signature)-1] ^= 1
+	return token[:i+1] + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// swapClaims replaces the payload of a signed token, keeping its signature.
+func swapClaims(t *testing.T, token string, claims map[string]any) string {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	parts[1] = encodeSegment(t, claims)
+	return strings.Join(parts, ".")
+}
+
+func TestJWTVerifierVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	secret := []byte("shared-secret")
+	rsaKeys := map[string]*rsa.PublicKey{"k1": &rsaKey.PublicKey}
+
+	claims := func(changes map[string]any) map[string]any {
+		c := map[string]any{
+			"sub": "alice",
+			"iss": "https://lms.example",
+			"aud": "userapi",
+			"exp": testNow.Add(time.Hour).Unix(),
+		}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	rs256 := map[string]any{"alg": "RS256", "kid": "k1"}
+	hs256 := map[string]any{"alg": "HS256"}
+
+	tests := []struct {
+		name    string
+		token   string
+		rsaOnly bool
+		wantErr error
+	}{
+		{name: "valid RS256", token: signRS256(t, rs256, claims(nil), rsaKey)},
+		{name: "valid HS256", token: signHS256(t, hs256, claims(nil), secret)},
+		{name: "audience list", token: signRS256(t, rs256, claims(map[string]any{"aud": []string{"other", "userapi"}}), rsaKey)},
+		{name: "expired within clock skew", token: signRS256(t, rs256, claims(map[string]any{"exp": testNow.Add(-10 * time.Second).Unix()}), rsaKey)},
+		{
+			name:    "alg none",
+			token:   encodeSegment(t, map[string]any{"alg": "none"}) + "." + encodeSegment(t, claims(nil)) + ".",
+			wantErr: ErrUnsupportedAlg,
+		},
+		{
+			name:    "HS256 signed with the RSA public key",
+			token:   signHS256(t, map[string]any{"alg": "HS256", "kid": "k1"}, claims(nil), publicDER),
+			rsaOnly: true,
+			wantErr: ErrUnsupportedAlg,
+		},
+		{
+			name:    "HS256 signed with the RSA public key next to a secret",
+			token:   signHS256(t, map[string]any{"alg": "HS256", "kid": "k1"}, claims(nil), publicDER),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "expired",
+			token:   signRS256(t, rs256, claims(map[string]any{"exp": testNow.Add(-time.Minute).Unix()}), rsaKey),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "no exp",
+			token:   signRS256(t, rs256, claims(map[string]any{"exp": nil}), rsaKey),
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "nbf in the future",
+			token:   signRS256(t, rs256, claims(map[string]any{"nbf": testNow.Add(time.Minute).Unix()}), rsaKey),
+			wantErr: ErrTokenNotYetValid,
+		},
+		{
+			name:    "wrong issuer",
+			token:   signRS256(t, rs256, claims(map[string]any{"iss": "https://evil.example"}), rsaKey),
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "wrong audience",
+			token:   signRS256(t, rs256, claims(map[string]any{"aud": "filestorage"}), rsaKey),
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "unknown kid",
+			token:   signRS256(t, map[string]any{"alg": "RS256", "kid": "k2"}, claims(nil), rsaKey),
+			wantErr: ErrUnknownKey,
+		},
+		{
+			name:    "signed by another key",
+			token:   signRS256(t, rs256, claims(nil), otherKey),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "tampered RS256 signature",
+			token:   tamper(signRS256(t, rs256, claims(nil), rsaKey)),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "tampered HS256 signature",
+			token:   tamper(signHS256(t, hs256, claims(nil), secret)),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "tampered claims",
+			token:   swapClaims(t, signRS256(t, rs256, claims(nil), rsaKey), claims(map[string]any{"sub": "mallory"})),
+			wantErr: ErrInvalidSignature,
+		},
+		{name: "not a JWT", token: "abc.def", wantErr: ErrMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hmacSecret := secret
+			if tt.rsaOnly {
+				hmacSecret = nil
+			}
+			v := NewJWTVerifier(hmacSecret, rsaKeys, "https://lms.example", "userapi")
+			v.now = func() time.Time { return testNow }
+
+			got, err := v.Verify(tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got.Subject != "alice" {
+				t.Errorf("Verify() subject = %q, want alice", got.Subject)
+			}
+		})
+	}
+}
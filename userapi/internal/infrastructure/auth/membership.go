@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Membership is the local course table: which works belong to a course and
+// who teaches or attends it. A work not listed in any course is a course of
+// its own, with the work id as course id.
+type Membership struct {
+	courseOf map[string]string
+	members  map[string]map[string]Role
+	global   map[string]Role
+}
+
+type membershipFile struct {
+	Courses map[string]struct {
+		Works   []string          `json:"works"`
+		Members map[string]string `json:"members"`
+	} `json:"courses"`
+	// Global grants a role in every course, e.g. to an automation key.
+	Global map[string]string `json:"global"`
+}
+
+func LoadMembership(path string) (*Membership, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read membership: %w", err)
+	}
+	var file membershipFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse membership %s: %w", path, err)
+	}
+
+	m := &Membership{
+		courseOf: make(map[string]string),
+		members:  make(map[string]map[string]Role),
+		global:   make(map[string]Role),
+	}
+	for course, c := range file.Courses {
+		for _, work := range c.Works {
+			if other, ok := m.courseOf[work]; ok {
+				return nil, fmt.Errorf("membership %s: work %q is in courses %q and %q", path, work, other, course)
+			}
+			m.courseOf[work] = course
+		}
+		roles := make(map[string]Role, len(c.Members))
+		for subject, name := range c.Members {
+			role, err := ParseRole(name)
+			if err != nil {
+				return nil, fmt.Errorf("membership %s: course %q, %s: %w", path, course, subject, err)
+			}
+			roles[subject] = role
+		}
+		m.members[course] = roles
+	}
+	for subject, name := range file.Global {
+		role, err := ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("membership %s: global, %s: %w", path, subject, err)
+		}
+		m.global[subject] = role
+	}
+	return m, nil
+}
+
+// CourseOf returns the course a work belongs to.
+func (m *Membership) CourseOf(workID string) string {
+	if m != nil {
+		if course, ok := m.courseOf[workID]; ok {
+			return course
+		}
+	}
+	return workID
+}
+
+// RoleIn returns the caller's role for a work: the highest of the roles
+// granted by their credentials and by the table, globally or for the work's
+// course. Callers with no role at all are students.
+func (m *Membership) RoleIn(p Principal, workID string) Role {
+	course := m.CourseOf(workID)
+	role := higher(p.Role, p.Courses[course])
+	if m != nil {
+		role = higher(role, m.global[p.Subject])
+		role = higher(role, m.members[course][p.Subject])
+	}
+	if role == "" {
+		return RoleStudent
+	}
+	return role
+}
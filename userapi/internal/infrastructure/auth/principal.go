@@ -0,0 +1,31 @@
+package auth
+
+import "context"
+
+type Method string
+
+const (
+	MethodJWT    Method = "jwt"
+	MethodAPIKey Method = "api_key"
+)
+
+// Principal is the authenticated caller. Subject is the login used for
+// submissions and quotas; Role and Courses are what the credentials grant
+// globally and per course, on top of the membership table.
+type Principal struct {
+	Subject string
+	Method  Method
+	Role    Role
+	Courses map[string]Role
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
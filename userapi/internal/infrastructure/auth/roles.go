@@ -0,0 +1,47 @@
+package auth
+
+import "fmt"
+
+type Role string
+
+const (
+	RoleStudent    Role = "student"
+	RoleTA         Role = "ta"
+	RoleInstructor Role = "instructor"
+)
+
+func ParseRole(s string) (Role, error) {
+	switch Role(s) {
+	case RoleStudent, RoleTA, RoleInstructor:
+		return Role(s), nil
+	default:
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+}
+
+// IsStaff reports whether the role sees full reports and every submission of
+// its courses.
+func (r Role) IsStaff() bool {
+	return r == RoleTA || r == RoleInstructor
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleInstructor:
+		return 3
+	case RoleTA:
+		return 2
+	case RoleStudent:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// higher returns the more privileged of two roles.
+func higher(a, b Role) Role {
+	if b.rank() > a.rank() {
+		return b
+	}
+	return a
+}
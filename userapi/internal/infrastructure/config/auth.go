@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type AuthConfig struct {
+	// JWTHMACSecret verifies HS256/384/512 tokens.
+	JWTHMACSecret string
+	// JWKSFile holds the RSA public keys for RS256/384/512 tokens.
+	JWKSFile    string
+	JWTIssuer   string
+	JWTAudience string
+	// APIKeys maps static keys for automation to their subjects.
+	APIKeys map[string]string
+	// MembershipFile is the local course table with works and roles.
+	MembershipFile string
+}
+
+// LoadAuthConfig reads the credentials the gateway accepts. API_KEYS is a
+// comma-separated list of subject:key pairs.
+func LoadAuthConfig() (*AuthConfig, error) {
+	cfg := &AuthConfig{
+		JWTHMACSecret:  os.Getenv("JWT_HMAC_SECRET"),
+		JWKSFile:       os.Getenv("JWT_JWKS_FILE"),
+		JWTIssuer:      os.Getenv("JWT_ISSUER"),
+		JWTAudience:    os.Getenv("JWT_AUDIENCE"),
+		APIKeys:        make(map[string]string),
+		MembershipFile: os.Getenv("MEMBERSHIP_FILE"),
+	}
+
+	for _, entry := range strings.Split(os.Getenv("API_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		subject, key, ok := strings.Cut(entry, ":")
+		if !ok || subject == "" || key == "" {
+			return nil, fmt.Errorf("API_KEYS: expected subject:key, got %q", entry)
+		}
+		if _, dup := cfg.APIKeys[key]; dup {
+			return nil, fmt.Errorf("API_KEYS: key for %q is used more than once", subject)
+		}
+		cfg.APIKeys[key] = subject
+	}
+	return cfg, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DownstreamConfig tunes the clients of filestorage, plagiarism and
+// wordcloud.
+type DownstreamConfig struct {
+	// Timeout bounds how long one attempt may wait for response headers once
+	// the request is written; uploads and response bodies are bounded only by
+	// the inbound request.
+	Timeout time.Duration
+	// MaxAttempts applies to GET and HEAD; other calls are tried once.
+	MaxAttempts int
+	RetryBase   time.Duration
+	// BreakerThreshold consecutive failures open a service's circuit for
+	// BreakerCooldown, during which calls fail without being sent.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func LoadDownstreamConfig() *DownstreamConfig {
+	return &DownstreamConfig{
+		Timeout:          durationEnv("DOWNSTREAM_TIMEOUT", 15*time.Second),
+		MaxAttempts:      intEnv("DOWNSTREAM_MAX_ATTEMPTS", 3),
+		RetryBase:        durationEnv("DOWNSTREAM_RETRY_BASE", 100*time.Millisecond),
+		BreakerThreshold: intEnv("DOWNSTREAM_BREAKER_THRESHOLD", 5),
+		BreakerCooldown:  durationEnv("DOWNSTREAM_BREAKER_COOLDOWN", 30*time.Second),
+	}
+}
+
+// RequestTimeout bounds each inbound request, and with it every downstream
+// call made for it. Event streams and exports are not bounded.
+func RequestTimeout() time.Duration {
+	return durationEnv("REQUEST_TIMEOUT", 30*time.Second)
+}
+
+func durationEnv(key string, defaultValue time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func intEnv(key string, defaultValue int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Routes that can be limited separately; everything else under
+// authentication counts against RouteDefault.
+const (
+	RouteSubmit    = "submit"
+	RouteWordcloud = "wordcloud"
+	RouteDownload  = "download"
+	RouteExport    = "export"
+	RouteEvents    = "events"
+	RouteDefault   = "default"
+)
+
+// RateLimitRoutes lists every route name RATE_LIMITS accepts.
+var RateLimitRoutes = []string{RouteSubmit, RouteWordcloud, RouteDownload, RouteExport, RouteEvents, RouteDefault}
+
+const defaultRateLimits = "submit=20/m:5,wordcloud=20/m:5,export=10/m:2,default=300/m:60"
+
+// RateLimit is a token bucket: Burst requests at once and Requests per Per
+// on average. Zero Requests means unlimited.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Requests > 0
+}
+
+type RateLimitConfig struct {
+	Routes map[string]RateLimit
+	// TrustForwardedFor keys anonymous callers by the last X-Forwarded-For
+	// address, which is only safe behind a proxy that appends it.
+	TrustForwardedFor bool
+}
+
+// For returns the limit of a route, falling back to the default one.
+func (c *RateLimitConfig) For(route string) RateLimit {
+	if limit, ok := c.Routes[route]; ok {
+		return limit
+	}
+	return c.Routes[RouteDefault]
+}
+
+// LoadRateLimitConfig reads RATE_LIMITS, a comma-separated list of
+// route=N/unit[:burst] entries (unit is s, m or h; burst defaults to N) or
+// route=off, applied on top of the defaults.
+func LoadRateLimitConfig() (*RateLimitConfig, error) {
+	cfg := &RateLimitConfig{
+		Routes:            make(map[string]RateLimit),
+		TrustForwardedFor: os.Getenv("RATE_LIMIT_TRUST_FORWARDED_FOR") == "true",
+	}
+	if err := parseRateLimits(defaultRateLimits, cfg.Routes); err != nil {
+		return nil, err
+	}
+	if err := parseRateLimits(os.Getenv("RATE_LIMITS"), cfg.Routes); err != nil {
+		return nil, fmt.Errorf("RATE_LIMITS: %w", err)
+	}
+	return cfg, nil
+}
+
+func parseRateLimits(spec string, routes map[string]RateLimit) error {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("expected route=N/unit[:burst], got %q", entry)
+		}
+		route, value = strings.TrimSpace(route), strings.TrimSpace(value)
+		if !slices.Contains(RateLimitRoutes, route) {
+			return fmt.Errorf("unknown route %q", route)
+		}
+		limit, err := parseRateLimit(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", route, err)
+		}
+		routes[route] = limit
+	}
+	return nil
+}
+
+func parseRateLimit(value string) (RateLimit, error) {
+	if value == "off" {
+		return RateLimit{}, nil
+	}
+
+	rate, burstValue, hasBurst := strings.Cut(value, ":")
+	count, unit, ok := strings.Cut(rate, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("expected N/unit, got %q", rate)
+	}
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid request count %q", count)
+	}
+
+	limit := RateLimit{Requests: requests, Burst: requests}
+	switch unit {
+	case "s":
+		limit.Per = time.Second
+	case "m":
+		limit.Per = time.Minute
+	case "h":
+		limit.Per = time.Hour
+	default:
+		return RateLimit{}, fmt.Errorf("unknown unit %q, expected s, m or h", unit)
+	}
+
+	if hasBurst {
+		limit.Burst, err = strconv.Atoi(burstValue)
+		if err != nil || limit.Burst <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid burst %q", burstValue)
+		}
+	}
+	return limit, nil
+}
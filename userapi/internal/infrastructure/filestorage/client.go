@@ -1,69 +1,271 @@
 package filestorage
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"strconv"
 	"strings"
-	"time"
+
+	"userapi/internal/application/dto"
 )
 
+var ErrNotFound = errors.New("not found")
+
+// APIError is an error response from filestorage that the client caused,
+// e.g. a rejected upload, so its message can be shown to the user.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("filestorage: status %d: %s: %s", e.Status, e.Code, e.Message)
+}
+
+// readAPIError turns a 4xx JSON error body into an *APIError; anything else
+// becomes a plain error with the status and body.
+func readAPIError(resp *http.Response, op string) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		var payload struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(msg, &payload) == nil && payload.Message != "" {
+			return &APIError{Status: resp.StatusCode, Code: payload.Error, Message: payload.Message}
+		}
+	}
+	return fmt.Errorf("%s: status %d: %s", op, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// ErrPresignUnavailable means filestorage cannot hand out direct download
+// URLs (non-S3 backend or presigning disabled); the file has to be proxied.
+var ErrPresignUnavailable = errors.New("presigned downloads unavailable")
+
+// ErrReportsUnavailable means filestorage cannot reach plagiarism, so an
+// export cannot include reports.
+var ErrReportsUnavailable = errors.New("plagiarism reports unavailable")
+
+// ErrQuarantined means filestorage's malware scanner flagged the file. It is
+// kept for review but cannot be downloaded or checked.
+var ErrQuarantined = errors.New("submission quarantined")
+
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
 }
 
 const submitPath = "/submit"
+const listSubmissionsPath = "/submissions"
 const downloadPath = "/submissions/download"
+const downloadURLPath = "/submissions/download-url"
+const quotaPath = "/quota"
+const listPageSize = 500
 
-func NewClient(baseURL string) *Client {
+// NewClient expects an httpClient without an overall timeout: exports take
+// as long as the archive does, bounded by the caller's context.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
 	return &Client{
 		baseURL:    strings.TrimRight(baseURL, "/"),
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: httpClient,
 	}
 }
 
-func (c *Client) UploadSubmission(ctx context.Context, assignmentID, login string, data []byte, filename, contentType string) (string, error) {
-	var body bytes.Buffer
-	writer := multipart.NewWriter(&body)
+// UploadSubmission streams the file to filestorage. A non-empty
+// idempotencyKey is sent as Idempotency-Key; replayed reports that
+// filestorage returned the upload made earlier with that key.
+func (c *Client) UploadSubmission(ctx context.Context, assignmentID, login, idempotencyKey string, file io.Reader, filename, contentType string) (string, bool, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid filestorage url: %w", err)
+	}
+	u.Path = submitPath
 
-	if err := writer.WriteField("assignment_id", assignmentID); err != nil {
-		return "", err
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeSubmitForm(form, assignmentID, login, file, filename, contentType))
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		_ = body.CloseWithError(err)
+		return "", false, err
 	}
-	if err := writer.WriteField("login", login); err != nil {
-		return "", err
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
 	}
 
-	part, err := writer.CreateFormFile("file", filename)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
-	if _, err := io.Copy(part, bytes.NewReader(data)); err != nil {
-		return "", err
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", false, readAPIError(resp, "upload to filestorage failed")
 	}
-	if err := writer.Close(); err != nil {
-		return "", err
+
+	var payload struct {
+		SubmissionID string `json:"submission_id"`
+		Status       string `json:"status"`
+		Signature    string `json:"signature"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", false, err
+	}
+	if payload.SubmissionID == "" {
+		return "", false, fmt.Errorf("upload to filestorage failed: empty submission_id")
+	}
+	if payload.Status == "quarantined" {
+		return "", false, fmt.Errorf("%w: submission_id=%s signature=%q", ErrQuarantined, payload.SubmissionID, payload.Signature)
+	}
+	return payload.SubmissionID, resp.Header.Get("Idempotent-Replayed") == "true", nil
+}
+
+func writeSubmitForm(form *multipart.Writer, assignmentID, login string, file io.Reader, filename, contentType string) error {
+	if err := form.WriteField("assignment_id", assignmentID); err != nil {
+		return err
+	}
+	if err := form.WriteField("login", login); err != nil {
+		return err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(filename)))
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	return form.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+func (c *Client) ListSubmissions(ctx context.Context, assignmentID string) ([]dto.Submission, error) {
+	submissions := make([]dto.Submission, 0)
+	cursor := ""
+	for {
+		page, next, err := c.listSubmissionsPage(ctx, assignmentID, cursor)
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, page...)
+		if next == "" {
+			return submissions, nil
+		}
+		cursor = next
+	}
+}
+
+func (c *Client) listSubmissionsPage(ctx context.Context, assignmentID, cursor string) ([]dto.Submission, string, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid filestorage url: %w", err)
+	}
+	u.Path = listSubmissionsPath
+	q := u.Query()
+	q.Set("assignment_id", assignmentID)
+	q.Set("limit", strconv.Itoa(listPageSize))
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, "", fmt.Errorf("list submissions: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var payload struct {
+		Submissions []dto.Submission `json:"submissions"`
+		NextCursor  string           `json:"next_cursor"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, "", err
 	}
+	return payload.Submissions, payload.NextCursor, nil
+}
 
+// DownloadSubmission streams the file; the caller must close the body.
+func (c *Client) DownloadSubmission(ctx context.Context, submissionID string) (io.ReadCloser, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filestorage url: %w", err)
+	}
+	u.Path = downloadPath
+	q := u.Query()
+	q.Set("submission_id", submissionID)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, ErrQuarantined
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("download submission: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) DownloadURL(ctx context.Context, submissionID string) (string, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return "", fmt.Errorf("invalid filestorage url: %w", err)
 	}
-	u.Path = submitPath
+	u.Path = downloadURLPath
+	q := u.Query()
+	q.Set("submission_id", submissionID)
+	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if contentType != "" {
-		req.Header.Set("X-File-Content-Type", contentType)
-	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -71,31 +273,117 @@ func (c *Client) UploadSubmission(ctx context.Context, assignmentID, login strin
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", ErrNotFound
+	case http.StatusForbidden:
+		return "", ErrQuarantined
+	case http.StatusNotImplemented:
+		return "", ErrPresignUnavailable
+	default:
 		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return "", fmt.Errorf("upload to filestorage failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		return "", fmt.Errorf("download url: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
 	}
 
 	var payload struct {
-		SubmissionID string `json:"submission_id"`
+		URL string `json:"url"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
 		return "", err
 	}
-	if payload.SubmissionID == "" {
-		return "", fmt.Errorf("upload to filestorage failed: empty submission_id")
+	if payload.URL == "" {
+		return "", fmt.Errorf("download url: empty url")
 	}
-	return payload.SubmissionID, nil
+	return payload.URL, nil
 }
 
-func (c *Client) DownloadSubmission(ctx context.Context, submissionID string) ([]byte, error) {
+// GetSubmission returns which work and author a submission belongs to.
+func (c *Client) GetSubmission(ctx context.Context, submissionID string) (*dto.SubmissionRef, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid filestorage url: %w", err)
 	}
-	u.Path = downloadPath
+	u.Path = listSubmissionsPath + "/" + url.PathEscape(submissionID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// filestorage answers 400 to ids that are not UUIDs; to the gateway's
+	// callers such a submission simply does not exist.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("get submission: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var payload struct {
+		SubmissionID string `json:"submission_id"`
+		AssignmentID string `json:"assignment_id"`
+		AuthorID     string `json:"author_id"`
+		Status       string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return &dto.SubmissionRef{
+		SubmissionID: payload.SubmissionID,
+		WorkID:       payload.AssignmentID,
+		AuthorID:     payload.AuthorID,
+		Status:       payload.Status,
+	}, nil
+}
+
+func (c *Client) DeleteSubmission(ctx context.Context, assignmentID, submissionID string) error {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid filestorage url: %w", err)
+	}
+	u.Path = listSubmissionsPath + "/" + url.PathEscape(submissionID)
 	q := u.Query()
-	q.Set("submission_id", submissionID)
+	q.Set("assignment_id", assignmentID)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("delete submission: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (c *Client) GetQuota(ctx context.Context, assignmentID, login string) (*dto.Quota, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filestorage url: %w", err)
+	}
+	u.Path = quotaPath
+	q := u.Query()
+	q.Set("author_id", login)
+	q.Set("assignment_id", assignmentID)
 	u.RawQuery = q.Encode()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
@@ -110,13 +398,53 @@ func (c *Client) DownloadSubmission(ctx context.Context, submissionID string) ([
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("download submission: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		return nil, readAPIError(resp, "get quota")
+	}
+
+	var quota dto.Quota
+	if err := json.NewDecoder(resp.Body).Decode(&quota); err != nil {
+		return nil, err
+	}
+	quota.WorkID = assignmentID
+	quota.Login = login
+	return &quota, nil
+}
+
+// ExportAssignment streams the assignment's zip archive; the caller must
+// close the body.
+func (c *Client) ExportAssignment(ctx context.Context, assignmentID string, includeReports bool) (io.ReadCloser, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filestorage url: %w", err)
+	}
+	u.Path = "/assignments/" + assignmentID + "/export"
+	u.RawPath = "/assignments/" + url.PathEscape(assignmentID) + "/export"
+	if includeReports {
+		u.RawQuery = "include_reports=true"
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	return data, nil
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	case http.StatusNotImplemented:
+		resp.Body.Close()
+		return nil, ErrReportsUnavailable
+	default:
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("export assignment: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
 }
@@ -0,0 +1,94 @@
+package httpclient
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while a service
+// is considered down.
+var ErrCircuitOpen = errors.New("circuit open")
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// breaker opens after threshold consecutive failures. Once cooldown has
+// passed a single probe is let through: success closes it again, failure
+// restarts the cooldown.
+type breaker struct {
+	service   string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func newBreaker(service string, threshold int, cooldown time.Duration) *breaker {
+	return &breaker{service: service, threshold: threshold, cooldown: cooldown}
+}
+
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = stateHalfOpen
+		return nil
+	case stateHalfOpen:
+		// The probe is still in flight.
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != stateClosed {
+		log.Printf("%s: circuit closed", b.service)
+	}
+	b.state = stateClosed
+	b.failures = 0
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == stateHalfOpen || b.failures >= b.threshold {
+		if b.state != stateOpen {
+			log.Printf("%s: circuit open for %s after %d consecutive failures", b.service, b.cooldown, b.failures)
+		}
+		b.state = stateOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// cancelled is called when the caller gave up before an outcome was known;
+// a half-open breaker lets the next call probe instead.
+func (b *breaker) cancelled() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == stateHalfOpen {
+		b.state = stateOpen
+		b.openedAt = time.Now().Add(-b.cooldown)
+	}
+}
@@ -0,0 +1,153 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"userapi/internal/infrastructure/config"
+)
+
+// ErrTimeout means the service did not answer an attempt within the
+// downstream timeout.
+var ErrTimeout = errors.New("no response in time")
+
+// Transport sends requests to one downstream service. Once an attempt has
+// written its request, body included, the response headers must arrive
+// within the timeout. GET and HEAD are retried on network errors, timeouts
+// and 5xx, and a circuit breaker fails calls fast while the service keeps
+// failing. Overall deadlines come from the request context.
+type Transport struct {
+	service     string
+	base        http.RoundTripper
+	timeout     time.Duration
+	maxAttempts int
+	retryBase   time.Duration
+	breaker     *breaker
+}
+
+// New returns a client for service. It has no overall timeout: calls are
+// bounded by their context, which handlers derive from the inbound request.
+func New(service string, cfg *config.DownstreamConfig) *http.Client {
+	return &http.Client{Transport: NewTransport(service, cfg)}
+}
+
+func NewTransport(service string, cfg *config.DownstreamConfig) *Transport {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	// The header timer starts only after the body is written, so a slow
+	// upload is bounded by the request context alone.
+	base.ResponseHeaderTimeout = cfg.Timeout
+	return &Transport{
+		service:     service,
+		base:        base,
+		timeout:     cfg.Timeout,
+		maxAttempts: cfg.MaxAttempts,
+		retryBase:   cfg.RetryBase,
+		breaker:     newBreaker(service, cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := 1
+	if retryable(req) {
+		attempts = t.maxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.try(req)
+		if attempt >= attempts || !shouldRetry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(t.backoff(attempt)):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+func (t *Transport) try(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		return nil, fmt.Errorf("%s: %w", t.service, err)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		switch {
+		case req.Context().Err() != nil:
+			t.breaker.cancelled()
+		case isHeaderTimeout(err):
+			// An upload can keep the service busy well past the timeout
+			// without it being down.
+			if hasBody(req) {
+				t.breaker.cancelled()
+			} else {
+				t.breaker.failure()
+			}
+			return nil, fmt.Errorf("%s: %w after %s", t.service, ErrTimeout, t.timeout)
+		default:
+			t.breaker.failure()
+		}
+		return nil, err
+	}
+
+	if isServerError(resp.StatusCode) {
+		t.breaker.failure()
+	} else {
+		t.breaker.success()
+	}
+	return resp, nil
+}
+
+// backoff doubles from retryBase with jitter, so that callers retrying
+// together do not hit a recovering service at the same moment.
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.retryBase << (attempt - 1)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable allows retries only for idempotent methods without a body to
+// replay.
+func retryable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return !hasBody(req)
+}
+
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody
+}
+
+// isHeaderTimeout reports a ResponseHeaderTimeout; a timeout while
+// connecting is an ordinary network error.
+func isHeaderTimeout(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen)
+	}
+	return isServerError(resp.StatusCode)
+}
+
+// isServerError treats 501 as an answer: filestorage uses it for features
+// that are switched off.
+func isServerError(status int) bool {
+	return status >= 500 && status != http.StatusNotImplemented
+}
@@ -1,7 +1,7 @@
 package plagiarism
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -20,20 +20,15 @@ type Client struct {
 	httpClient *http.Client
 }
 
-const checksPath = "/checks"
-
-func NewClient(baseURL string) *Client {
+// NewClient expects an httpClient without an overall timeout: event streams
+// stay open until the caller's context ends.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
 	return &Client{
 		baseURL:    strings.TrimRight(baseURL, "/"),
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: httpClient,
 	}
 }
 
-type StartCheckResponse struct {
-	SubmissionID string `json:"submission_id"`
-	Status       string `json:"status"`
-}
-
 type WorkReportsResponse struct {
 	WorkID  string        `json:"work_id"`
 	Reports []CheckReport `json:"reports"`
@@ -58,29 +53,20 @@ type MatchResult struct {
 	Similarity        float64 `json:"similarity"`
 	SelfSize          int64   `json:"self_size"`
 	OtherSize         int64   `json:"other_size"`
+	OtherDeleted      bool    `json:"other_deleted"`
 }
 
-func (c *Client) StartCheck(ctx context.Context, submissionID, workID string) (*StartCheckResponse, error) {
-	payload := map[string]string{
-		"submission_id": submissionID,
-		"work_id":       workID,
-	}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Client) GetReports(ctx context.Context, workID string) (*WorkReportsResponse, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid plagiarism url: %w", err)
 	}
-	u.Path = checksPath
+	u.Path = fmt.Sprintf("/works/%s/reports", url.PathEscape(workID))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -88,27 +74,29 @@ func (c *Client) StartCheck(ctx context.Context, submissionID, workID string) (*
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusAccepted {
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
 		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("start check failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		return nil, fmt.Errorf("get reports failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
 	}
 
-	var parsed StartCheckResponse
+	var parsed WorkReportsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
 		return nil, err
 	}
-	if parsed.SubmissionID == "" {
-		return nil, fmt.Errorf("start check failed: empty submission_id")
-	}
 	return &parsed, nil
 }
 
-func (c *Client) GetReports(ctx context.Context, workID string) (*WorkReportsResponse, error) {
+// GetCheck returns the report of one submission; ErrNotFound means no check
+// has been started for it yet.
+func (c *Client) GetCheck(ctx context.Context, workID, submissionID string) (*CheckReport, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid plagiarism url: %w", err)
 	}
-	u.Path = fmt.Sprintf("/works/%s/reports", url.PathEscape(workID))
+	u.Path = fmt.Sprintf("/works/%s/submissions/%s", url.PathEscape(workID), url.PathEscape(submissionID))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
@@ -126,12 +114,90 @@ func (c *Client) GetReports(ctx context.Context, workID string) (*WorkReportsRes
 	}
 	if resp.StatusCode != http.StatusOK {
 		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return nil, fmt.Errorf("get reports failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		return nil, fmt.Errorf("get check failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
 	}
 
-	var parsed WorkReportsResponse
+	var parsed struct {
+		Report CheckReport `json:"report"`
+	}
 	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
 		return nil, err
 	}
-	return &parsed, nil
+	return &parsed.Report, nil
+}
+
+type CheckEvent struct {
+	WorkID       string    `json:"work_id"`
+	SubmissionID string    `json:"submission_id"`
+	AuthorID     string    `json:"author_id"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+// EventStream reads the Server-Sent Events of GET /works/{id}/events.
+type EventStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+// OpenEvents subscribes to the check status changes of a work.
+func (c *Client) OpenEvents(ctx context.Context, workID string) (*EventStream, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plagiarism url: %w", err)
+	}
+	u.Path = fmt.Sprintf("/works/%s/events", url.PathEscape(workID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("open events failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return &EventStream{body: resp.Body, scanner: bufio.NewScanner(resp.Body)}, nil
+}
+
+// Next blocks until the next check event. It returns io.EOF once plagiarism
+// closes the stream.
+func (s *EventStream) Next() (CheckEvent, error) {
+	var data strings.Builder
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			if data.Len() == 0 {
+				continue
+			}
+			var event CheckEvent
+			if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+				return CheckEvent{}, err
+			}
+			return event, nil
+		}
+		// Comments and the event name carry nothing the gateway needs.
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(value, " "))
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return CheckEvent{}, err
+	}
+	return CheckEvent{}, io.EOF
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
 }
@@ -2,6 +2,9 @@ package plagiarism
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log"
 
 	"userapi/internal/application/dto"
 )
@@ -14,17 +17,6 @@ func NewService(client *Client) *Service {
 	return &Service{client: client}
 }
 
-func (s *Service) StartCheck(ctx context.Context, submissionID, workID string) (dto.CheckStartResult, error) {
-	resp, err := s.client.StartCheck(ctx, submissionID, workID)
-	if err != nil {
-		return dto.CheckStartResult{}, err
-	}
-	return dto.CheckStartResult{
-		SubmissionID: resp.SubmissionID,
-		Status:       resp.Status,
-	}, nil
-}
-
 func (s *Service) GetReports(ctx context.Context, workID string) (*dto.WorkReportsResponse, error) {
 	resp, err := s.client.GetReports(ctx, workID)
 	if err != nil {
@@ -33,28 +25,7 @@ func (s *Service) GetReports(ctx context.Context, workID string) (*dto.WorkRepor
 
 	reports := make([]dto.CheckReport, 0, len(resp.Reports))
 	for _, rep := range resp.Reports {
-		matches := make([]dto.MatchResult, 0, len(rep.Matches))
-		for _, m := range rep.Matches {
-			matches = append(matches, dto.MatchResult{
-				OtherSubmissionID: m.OtherSubmissionID,
-				OtherAuthorID:     m.OtherAuthorID,
-				Equal:             m.Equal,
-				MatchedBytes:      m.MatchedBytes,
-				TotalBytes:        m.TotalBytes,
-				Similarity:        m.Similarity,
-				SelfSize:          m.SelfSize,
-				OtherSize:         m.OtherSize,
-			})
-		}
-		reports = append(reports, dto.CheckReport{
-			WorkID:       rep.WorkID,
-			SubmissionID: rep.SubmissionID,
-			AuthorID:     rep.AuthorID,
-			Status:       rep.Status,
-			CreatedAt:    rep.CreatedAt,
-			Error:        rep.Error,
-			Matches:      matches,
-		})
+		reports = append(reports, toReportDTO(rep))
 	}
 
 	return &dto.WorkReportsResponse{
@@ -62,3 +33,75 @@ func (s *Service) GetReports(ctx context.Context, workID string) (*dto.WorkRepor
 		Reports: reports,
 	}, nil
 }
+
+func (s *Service) GetCheck(ctx context.Context, workID, submissionID string) (*dto.CheckReport, error) {
+	rep, err := s.client.GetCheck(ctx, workID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	report := toReportDTO(*rep)
+	return &report, nil
+}
+
+// WatchEvents streams the check events of a work until ctx ends or
+// plagiarism closes the stream, then closes the channel.
+func (s *Service) WatchEvents(ctx context.Context, workID string) (<-chan dto.CheckEvent, error) {
+	stream, err := s.client.OpenEvents(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan dto.CheckEvent)
+	go func() {
+		defer close(events)
+		defer stream.Close()
+		for {
+			ev, err := stream.Next()
+			if err != nil {
+				if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+					log.Printf("plagiarism events: work_id=%s stream failed: %v", workID, err)
+				}
+				return
+			}
+			select {
+			case events <- dto.CheckEvent{
+				WorkID:       ev.WorkID,
+				SubmissionID: ev.SubmissionID,
+				AuthorID:     ev.AuthorID,
+				Status:       ev.Status,
+				Error:        ev.Error,
+				At:           ev.At,
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+func toReportDTO(rep CheckReport) dto.CheckReport {
+	matches := make([]dto.MatchResult, 0, len(rep.Matches))
+	for _, m := range rep.Matches {
+		matches = append(matches, dto.MatchResult{
+			OtherSubmissionID: m.OtherSubmissionID,
+			OtherAuthorID:     m.OtherAuthorID,
+			Equal:             m.Equal,
+			MatchedBytes:      m.MatchedBytes,
+			TotalBytes:        m.TotalBytes,
+			Similarity:        m.Similarity,
+			SelfSize:          m.SelfSize,
+			OtherSize:         m.OtherSize,
+			OtherDeleted:      m.OtherDeleted,
+		})
+	}
+	return dto.CheckReport{
+		WorkID:       rep.WorkID,
+		SubmissionID: rep.SubmissionID,
+		AuthorID:     rep.AuthorID,
+		Status:       rep.Status,
+		CreatedAt:    rep.CreatedAt,
+		Error:        rep.Error,
+		Matches:      matches,
+	}
+}
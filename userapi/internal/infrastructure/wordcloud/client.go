@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 )
 
 type Client struct {
@@ -18,10 +17,10 @@ type Client struct {
 const wordcloudPath = "/wordcloud"
 const maxErrBody = 4096
 
-func NewClient(baseURL string) *Client {
+func NewClient(baseURL string, httpClient *http.Client) *Client {
 	return &Client{
 		baseURL:    strings.TrimRight(baseURL, "/"),
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: httpClient,
 	}
 }
 